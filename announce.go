@@ -0,0 +1,78 @@
+package arxiv
+
+import "time"
+
+// arXiv's daily submission deadline and announcement time, in US Eastern time
+const (
+	announcementCutoffHour = 14
+	announcementHour       = 20
+)
+
+// announcementDelay is how many days after the deadline day a submission is announced,
+// by the weekday of the deadline: Thursday 14:00 to Friday 14:00 goes out on Sunday and
+// Friday 14:00 to Monday 14:00 on Monday
+var announcementDelay = [7]int{
+	time.Sunday:    1,
+	time.Monday:    0,
+	time.Tuesday:   0,
+	time.Wednesday: 0,
+	time.Thursday:  0,
+	time.Friday:    2,
+	time.Saturday:  2,
+}
+
+// AnnouncementDate returns when arXiv announced, or will announce, the paper's first
+// version according to its regular schedule. Submissions are collected until a 14:00 US
+// Eastern deadline each weekday and announced at 20:00 Eastern: the batches closing Monday
+// to Thursday that same evening, the one closing Friday on Sunday evening, and weekend
+// submissions with Monday's batch. The result is in US Eastern time. PublishedAt is taken
+// as the submission time; holidays, which shift the schedule, and papers held for
+// moderation are not accounted for. It returns the zero time if PublishedAt is unset.
+func (p *Paper) AnnouncementDate() time.Time {
+	if p.PublishedAt.IsZero() {
+		return time.Time{}
+	}
+
+	submitted := p.PublishedAt.In(easternZone(p.PublishedAt))
+	deadline := time.Date(submitted.Year(), submitted.Month(), submitted.Day(), 0, 0, 0, 0, time.UTC)
+	if submitted.Hour() >= announcementCutoffHour {
+		deadline = deadline.AddDate(0, 0, 1)
+	}
+	day := deadline.AddDate(0, 0, announcementDelay[deadline.Weekday()])
+
+	// Clocks change at 2:00, so the date alone decides the evening's offset
+	offset := -5 * time.Hour
+	if start, end := usDSTDates(day.Year()); !day.Before(start) && day.Before(end) {
+		offset = -4 * time.Hour
+	}
+	announced := day.Add(announcementHour*time.Hour - offset)
+	return announced.In(easternZone(announced))
+}
+
+// easternZone returns the fixed US Eastern zone, EST or EDT, in effect at t. The
+// rules are computed rather than loaded so the result doesn't depend on the system's
+// time zone database.
+func easternZone(t time.Time) *time.Location {
+	start, end := usDSTDates(t.UTC().Year())
+	// Clocks spring forward at 2:00 EST (7:00 UTC) and fall back at 2:00 EDT (6:00 UTC)
+	if !t.Before(start.Add(7*time.Hour)) && t.Before(end.Add(6*time.Hour)) {
+		return time.FixedZone("EDT", -4*60*60)
+	}
+	return time.FixedZone("EST", -5*60*60)
+}
+
+// usDSTDates returns the Sundays, as UTC midnights, on which US daylight saving time starts
+// and ends in year: the second Sunday of March and the first Sunday of November since
+// 2007, the first Sunday of April and the last Sunday of October before
+func usDSTDates(year int) (start, end time.Time) {
+	if year >= 2007 {
+		return nthSunday(year, time.March, 2), nthSunday(year, time.November, 1)
+	}
+	return nthSunday(year, time.April, 1), nthSunday(year, time.November, 1).AddDate(0, 0, -7)
+}
+
+// nthSunday returns the nth Sunday of the month as a UTC midnight
+func nthSunday(year int, month time.Month, n int) time.Time {
+	first := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+	return first.AddDate(0, 0, (7-int(first.Weekday()))%7+7*(n-1))
+}
@@ -0,0 +1,51 @@
+package arxiv
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPaper_AnnouncementDate(t *testing.T) {
+	utc := func(value string) time.Time {
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			t.Fatalf("Invalid time %q: %v", value, err)
+		}
+		return parsed
+	}
+
+	tests := []struct {
+		name      string
+		published string
+		announced string
+	}{
+		{"just before the cutoff", "2023-01-10T13:59:00-05:00", "2023-01-10T20:00:00-05:00"},
+		{"at the cutoff", "2023-01-10T14:00:00-05:00", "2023-01-11T20:00:00-05:00"},
+		{"previous evening", "2023-01-10T02:00:00Z", "2023-01-10T20:00:00-05:00"},
+		{"Thursday after the cutoff", "2023-06-15T14:30:00-04:00", "2023-06-18T20:00:00-04:00"},
+		{"Friday before the cutoff", "2023-06-16T13:00:00-04:00", "2023-06-18T20:00:00-04:00"},
+		{"Friday after the cutoff", "2023-06-16T14:00:00-04:00", "2023-06-19T20:00:00-04:00"},
+		{"Saturday", "2023-06-17T10:00:00-04:00", "2023-06-19T20:00:00-04:00"},
+		{"Sunday afternoon", "2023-06-18T15:00:00-04:00", "2023-06-19T20:00:00-04:00"},
+		{"across the switch to daylight time", "2023-03-10T15:00:00-05:00", "2023-03-13T20:00:00-04:00"},
+		{"across the switch to standard time", "2023-11-03T15:00:00-04:00", "2023-11-06T20:00:00-05:00"},
+		{"pre-2007 daylight time", "2005-10-27T13:00:00-04:00", "2005-10-27T20:00:00-04:00"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			paper := &Paper{PublishedAt: utc(tt.published).UTC()}
+			got := paper.AnnouncementDate()
+			if expected := utc(tt.announced); !got.Equal(expected) {
+				t.Errorf("Expected announcement at %v, got %v", expected, got)
+			}
+			if got.Hour() != announcementHour {
+				t.Errorf("Expected the result in US Eastern time, got %v", got)
+			}
+		})
+	}
+
+	if got := (&Paper{}).AnnouncementDate(); !got.IsZero() {
+		t.Errorf("Expected the zero time for an unset PublishedAt, got %v", got)
+	}
+}
@@ -0,0 +1,78 @@
+package arxiv
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+var (
+	// New-style IDs since April 2007: YYMM.NNNN, or YYMM.NNNNN since January 2015
+	newStyleIDPattern = regexp.MustCompile(`^(\d{2}(?:0[1-9]|1[0-2]))\.(\d{4,5})(?:v([1-9]\d*))?$`)
+
+	// Old-style IDs until March 2007: archive[.SC]/YYMMNNN, e.g. "hep-th/9901001" or "math.GT/0309136"
+	oldStyleIDPattern = regexp.MustCompile(`^([a-z]+(?:-[a-z]+)*(?:\.[A-Z]{2})?)/(\d{2}(?:0[1-9]|1[0-2])\d{3})(?:v([1-9]\d*))?$`)
+)
+
+// ArxivID is a parsed arXiv identifier
+type ArxivID struct {
+	// Archive is the archive of an old-style ID, including any subject class, e.g. "hep-th"
+	// or "math.GT". It is empty for new-style IDs.
+	Archive string
+
+	// Number is the sequence part: "2301.00001" for new-style IDs, "9901001" for old-style ones
+	Number string
+
+	// Version is the version number, or 0 if the ID has no version suffix
+	Version int
+}
+
+// String returns the ID in its canonical form, e.g. "2301.00001v2" or "hep-th/9901001"
+func (id ArxivID) String() string {
+	s := id.Number
+	if id.Archive != "" {
+		s = id.Archive + "/" + s
+	}
+	if id.Version > 0 {
+		s += "v" + strconv.Itoa(id.Version)
+	}
+	return s
+}
+
+// IsValidArxivID reports whether id is a well-formed arXiv ID of either era, with an
+// optional version suffix. It checks the format only, not whether the paper exists.
+func IsValidArxivID(id string) bool {
+	_, err := ParseArxivID(id)
+	return err == nil
+}
+
+// ParseArxivID splits a new-style ("2301.00001v2") or old-style ("hep-th/9901001v1") arXiv ID
+// into its components. IDs with a malformed format, an impossible month or a sequence number
+// of the wrong length for their date fail with an ErrorTypeInvalidQuery error.
+func ParseArxivID(id string) (ArxivID, error) {
+	if m := newStyleIDPattern.FindStringSubmatch(id); m != nil {
+		yearMonth, number := m[1], m[2]
+		// Five-digit sequence numbers started in 1501; the scheme itself in 0704
+		if yearMonth >= "0704" && (len(number) == 5) == (yearMonth >= "1501") {
+			return ArxivID{Number: yearMonth + "." + number, Version: parseIDVersion(m[3])}, nil
+		}
+	}
+
+	if m := oldStyleIDPattern.FindStringSubmatch(id); m != nil {
+		// Old-style IDs ran from 1991 until March 2007
+		if yearMonth := m[2][:4]; yearMonth >= "9108" || yearMonth <= "0703" {
+			return ArxivID{Archive: m[1], Number: m[2], Version: parseIDVersion(m[3])}, nil
+		}
+	}
+
+	return ArxivID{}, NewAPIError(ErrorTypeInvalidQuery, fmt.Sprintf("invalid arXiv ID %q", id), nil)
+}
+
+// parseIDVersion converts a version suffix matched without its "v" to a number, 0 if empty
+func parseIDVersion(s string) int {
+	version, err := strconv.Atoi(s)
+	if err != nil {
+		return 0
+	}
+	return version
+}
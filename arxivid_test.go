@@ -0,0 +1,83 @@
+package arxiv
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseArxivID(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected ArxivID
+	}{
+		{"new style four digits", "0704.0001", ArxivID{Number: "0704.0001"}},
+		{"new style last four digit month", "1412.9999v3", ArxivID{Number: "1412.9999", Version: 3}},
+		{"new style five digits", "2301.00001", ArxivID{Number: "2301.00001"}},
+		{"new style with version", "1501.00001v12", ArxivID{Number: "1501.00001", Version: 12}},
+		{"old style", "hep-th/9901001", ArxivID{Archive: "hep-th", Number: "9901001"}},
+		{"old style with version", "quant-ph/0301001v2", ArxivID{Archive: "quant-ph", Number: "0301001", Version: 2}},
+		{"old style subject class", "math.GT/0309136", ArxivID{Archive: "math.GT", Number: "0309136"}},
+		{"old style first month", "hep-th/9108001", ArxivID{Archive: "hep-th", Number: "9108001"}},
+		{"old style last month", "cs/0703999v1", ArxivID{Archive: "cs", Number: "0703999", Version: 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseArxivID(tt.input)
+			if err != nil {
+				t.Fatalf("ParseArxivID(%q): unexpected error: %v", tt.input, err)
+			}
+			if got != tt.expected {
+				t.Errorf("ParseArxivID(%q): expected %+v, got %+v", tt.input, tt.expected, got)
+			}
+			if got.String() != tt.input {
+				t.Errorf("Expected String() to round-trip %q, got %q", tt.input, got.String())
+			}
+			if !IsValidArxivID(tt.input) {
+				t.Errorf("Expected IsValidArxivID(%q) to be true", tt.input)
+			}
+		})
+	}
+}
+
+func TestParseArxivID_Malformed(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"empty", ""},
+		{"whitespace", " 2301.00001"},
+		{"prefixed", "arXiv:2301.00001"},
+		{"url", "https://arxiv.org/abs/2301.00001"},
+		{"month zero", "2300.00001"},
+		{"month thirteen", "2313.00001"},
+		{"five digits before 2015", "1412.00001"},
+		{"four digits since 2015", "1501.0001"},
+		{"new style before April 2007", "0703.0001"},
+		{"three digit sequence", "2301.001"},
+		{"six digit sequence", "2301.000001"},
+		{"version zero", "2301.00001v0"},
+		{"empty version", "2301.00001v"},
+		{"uppercase version", "2301.00001V2"},
+		{"old style after March 2007", "hep-th/0704001"},
+		{"old style before August 1991", "hep-th/9107001"},
+		{"old style short number", "hep-th/990100"},
+		{"old style uppercase archive", "HEP-TH/9901001"},
+		{"old style bad subject class", "math.gt/0309136"},
+		{"old style missing archive", "/9901001"},
+		{"trailing text", "2301.00001v2.pdf"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if IsValidArxivID(tt.input) {
+				t.Errorf("Expected IsValidArxivID(%q) to be false", tt.input)
+			}
+			_, err := ParseArxivID(tt.input)
+			if !errors.Is(err, ErrInvalidQuery) {
+				t.Errorf("ParseArxivID(%q): expected ErrInvalidQuery, got %v", tt.input, err)
+			}
+		})
+	}
+}
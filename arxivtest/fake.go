@@ -0,0 +1,105 @@
+// Package arxivtest provides test doubles for code built on the arxiv package.
+package arxivtest
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/furudenipa/arxiv-go"
+)
+
+// FakeClient is an in-memory arxiv.Searcher with programmable responses
+type FakeClient struct {
+	// SearchFunc, when set, handles every Search call
+	SearchFunc func(ctx context.Context, query *arxiv.Query) (*arxiv.SearchResults, error)
+
+	// Papers is paged through by Start/MaxResults when SearchFunc is nil
+	Papers []arxiv.Paper
+
+	// Err, when set, is returned by every call
+	Err error
+
+	mu      sync.Mutex
+	queries []arxiv.Query
+}
+
+var _ arxiv.Searcher = (*FakeClient)(nil)
+
+// Search records the query and returns the programmed response
+func (f *FakeClient) Search(ctx context.Context, query *arxiv.Query) (*arxiv.SearchResults, error) {
+	if query == nil {
+		return nil, arxiv.NewAPIError(arxiv.ErrorTypeInvalidQuery, "query cannot be nil", nil)
+	}
+
+	f.mu.Lock()
+	f.queries = append(f.queries, *query)
+	f.mu.Unlock()
+
+	if f.Err != nil {
+		return nil, f.Err
+	}
+	if f.SearchFunc != nil {
+		return f.SearchFunc(ctx, query)
+	}
+
+	papers := f.Papers
+	if len(query.IDList) > 0 {
+		papers = nil
+		for _, id := range query.IDList {
+			if paper := f.find(id); paper != nil {
+				papers = append(papers, *paper)
+			}
+		}
+	}
+
+	start := min(query.Start, len(papers))
+	end := len(papers)
+	if query.MaxResults > 0 {
+		end = min(start+query.MaxResults, len(papers))
+	}
+	page := append([]arxiv.Paper(nil), papers[start:end]...)
+
+	return &arxiv.SearchResults{
+		Papers:       page,
+		TotalCount:   len(papers),
+		StartIndex:   start,
+		ItemsPerPage: len(page),
+	}, nil
+}
+
+// GetByID returns the paper whose ID matches id, ignoring the version when id has none
+func (f *FakeClient) GetByID(ctx context.Context, id string) (*arxiv.Paper, error) {
+	if id == "" {
+		return nil, arxiv.NewAPIError(arxiv.ErrorTypeInvalidQuery, "id cannot be empty", nil)
+	}
+	if f.Err != nil {
+		return nil, f.Err
+	}
+
+	paper := f.find(id)
+	if paper == nil {
+		return nil, arxiv.NewAPIError(arxiv.ErrorTypeNotFound, fmt.Sprintf("paper with ID %s not found", id), nil)
+	}
+	result := *paper
+	return &result, nil
+}
+
+// Queries returns a copy of every query passed to Search so far
+func (f *FakeClient) Queries() []arxiv.Query {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]arxiv.Query(nil), f.queries...)
+}
+
+// find returns the first paper matching id exactly or by version-less prefix
+func (f *FakeClient) find(id string) *arxiv.Paper {
+	for i := range f.Papers {
+		paperID := f.Papers[i].ID
+		if paperID == id || strings.HasPrefix(paperID, id+"v") {
+			return &f.Papers[i]
+		}
+	}
+	return nil
+}
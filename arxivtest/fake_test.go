@@ -0,0 +1,87 @@
+package arxivtest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/furudenipa/arxiv-go"
+)
+
+func fakePapers(n int) []arxiv.Paper {
+	papers := make([]arxiv.Paper, n)
+	for i := range papers {
+		papers[i] = arxiv.Paper{
+			ID:    fmt.Sprintf("2301.%05dv1", i),
+			Title: fmt.Sprintf("Paper %d", i),
+		}
+	}
+	return papers
+}
+
+func TestFakeClient_Iterator(t *testing.T) {
+	fake := &FakeClient{Papers: fakePapers(5)}
+
+	iter := arxiv.NewQueryBuilder(fake).SearchQuery("test").MaxResults(2).Iterator(context.Background())
+	papers, err := iter.Collect()
+	if err != nil {
+		t.Fatalf("Collect error: %v", err)
+	}
+
+	if len(papers) != 5 {
+		t.Fatalf("Expected 5 papers, got %d", len(papers))
+	}
+
+	for i, paper := range papers {
+		if expected := fmt.Sprintf("Paper %d", i); paper.Title != expected {
+			t.Errorf("Expected paper %d title '%s', got '%s'", i, expected, paper.Title)
+		}
+	}
+
+	queries := fake.Queries()
+	if len(queries) != 3 {
+		t.Fatalf("Expected 3 paged queries, got %d", len(queries))
+	}
+	if queries[0].SearchQuery != "(test)" {
+		t.Errorf("Expected recorded search query '(test)', got '%s'", queries[0].SearchQuery)
+	}
+}
+
+func TestFakeClient_GetByID(t *testing.T) {
+	fake := &FakeClient{Papers: fakePapers(3)}
+
+	paper, err := fake.GetByID(context.Background(), "2301.00001")
+	if err != nil {
+		t.Fatalf("GetByID failed: %v", err)
+	}
+	if paper.ID != "2301.00001v1" {
+		t.Errorf("Expected ID '2301.00001v1', got '%s'", paper.ID)
+	}
+
+	_, err = fake.GetByID(context.Background(), "9999.99999")
+	if !arxiv.IsNotFound(err) {
+		t.Errorf("Expected not found error, got %v", err)
+	}
+}
+
+func TestFakeClient_SearchFuncAndErr(t *testing.T) {
+	fake := &FakeClient{
+		SearchFunc: func(ctx context.Context, query *arxiv.Query) (*arxiv.SearchResults, error) {
+			return &arxiv.SearchResults{Papers: fakePapers(1), TotalCount: 1}, nil
+		},
+	}
+
+	results, err := arxiv.NewQueryBuilder(fake).Category(arxiv.CategoryCSAI).Execute(context.Background())
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if len(results.Papers) != 1 {
+		t.Errorf("Expected 1 paper from SearchFunc, got %d", len(results.Papers))
+	}
+
+	fake.Err = errors.New("boom")
+	if _, err := fake.Search(context.Background(), &arxiv.Query{SearchQuery: "x"}); err != fake.Err {
+		t.Errorf("Expected programmed error, got %v", err)
+	}
+}
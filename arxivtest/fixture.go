@@ -0,0 +1,106 @@
+package arxivtest
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/furudenipa/arxiv-go"
+)
+
+// FixtureTransport is an http.RoundTripper serving saved arXiv responses from Dir.
+// Fixtures are keyed by FixtureKey. Requests with no fixture fail with an
+// ErrorTypeNotFound APIError, which the client returns as is. In Record mode, requests
+// are forwarded to Next (http.DefaultTransport if nil) and successful responses are
+// written to Dir.
+type FixtureTransport struct {
+	Dir    string
+	Record bool
+	Next   http.RoundTripper
+}
+
+// NewFileClient creates a client that replays XML fixtures from dir
+func NewFileClient(dir string) *arxiv.Client {
+	return arxiv.NewClientWithHTTPClient(&http.Client{Transport: &FixtureTransport{Dir: dir}})
+}
+
+// NewRecordingClient creates a client that queries arXiv and saves every response to dir
+func NewRecordingClient(dir string) *arxiv.Client {
+	return arxiv.NewClientWithHTTPClient(&http.Client{Transport: &FixtureTransport{Dir: dir, Record: true}})
+}
+
+// FixtureKey returns the fixture file name for a request, derived from a hash of its query parameters
+func FixtureKey(req *http.Request) string {
+	sum := sha256.Sum256([]byte(req.URL.Query().Encode()))
+	return hex.EncodeToString(sum[:8]) + ".xml"
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *FixtureTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	path := filepath.Join(t.Dir, FixtureKey(req))
+	if t.Record {
+		return t.record(req, path)
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, arxiv.NewAPIError(arxiv.ErrorTypeNotFound, fmt.Sprintf("no fixture %s", path), err)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fixture: %w", err)
+	}
+	return newResponse(req, http.StatusOK, data), nil
+}
+
+// record forwards req and saves a successful response body to path
+func (t *FixtureTransport) record(req *http.Request, path string) (*http.Response, error) {
+	next := t.Next
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	resp, err := next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	if resp.StatusCode == http.StatusOK {
+		if err := os.MkdirAll(t.Dir, 0o755); err != nil {
+			return nil, fmt.Errorf("failed to create fixture directory: %w", err)
+		}
+		if err := os.WriteFile(path, data, 0o644); err != nil {
+			return nil, fmt.Errorf("failed to write fixture: %w", err)
+		}
+	}
+
+	resp.Body = io.NopCloser(bytes.NewReader(data))
+	return resp, nil
+}
+
+// newResponse builds a minimal response for req with the given status and body
+func newResponse(req *http.Request, status int, body []byte) *http.Response {
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": []string{"application/atom+xml"}},
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
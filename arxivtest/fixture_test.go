@@ -0,0 +1,72 @@
+package arxivtest
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/furudenipa/arxiv-go"
+)
+
+const fixtureFeed = `<?xml version="1.0" encoding="UTF-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom">
+  <opensearch:totalResults xmlns:opensearch="http://a9.com/-/spec/opensearch/1.1/">1</opensearch:totalResults>
+  <opensearch:startIndex xmlns:opensearch="http://a9.com/-/spec/opensearch/1.1/">0</opensearch:startIndex>
+  <opensearch:itemsPerPage xmlns:opensearch="http://a9.com/-/spec/opensearch/1.1/">1</opensearch:itemsPerPage>
+  <entry>
+    <id>http://arxiv.org/abs/1234.5678v1</id>
+    <title>Recorded Paper</title>
+    <summary>Abstract</summary>
+    <published>2023-01-01T00:00:00Z</published>
+    <updated>2023-01-01T00:00:00Z</updated>
+    <author><name>Author One</name></author>
+  </entry>
+</feed>`
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestFixtureTransport_RecordAndReplay(t *testing.T) {
+	dir := t.TempDir()
+	query := &arxiv.Query{SearchQuery: "recorded", MaxResults: 1}
+
+	upstream := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		return newResponse(req, http.StatusOK, []byte(fixtureFeed)), nil
+	})
+	recorder := arxiv.NewClientWithHTTPClient(&http.Client{
+		Transport: &FixtureTransport{Dir: dir, Record: true, Next: upstream},
+	})
+	if _, err := recorder.Search(context.Background(), query); err != nil {
+		t.Fatalf("Recording search failed: %v", err)
+	}
+
+	replay := NewFileClient(dir)
+	results, err := replay.Search(context.Background(), query)
+	if err != nil {
+		t.Fatalf("Replay search failed: %v", err)
+	}
+	if len(results.Papers) != 1 || results.Papers[0].Title != "Recorded Paper" {
+		t.Errorf("Expected replayed 'Recorded Paper', got %+v", results.Papers)
+	}
+
+	_, err = replay.Search(context.Background(), &arxiv.Query{SearchQuery: "never recorded", MaxResults: 1})
+	if !arxiv.IsNotFound(err) {
+		t.Errorf("Expected not found error for missing fixture, got %v", err)
+	}
+}
+
+func TestFixtureKey(t *testing.T) {
+	a, _ := http.NewRequest("GET", "https://export.arxiv.org/api/query?search_query=x&max_results=1", nil)
+	b, _ := http.NewRequest("GET", "https://export.arxiv.org/api/query?max_results=1&search_query=x", nil)
+	c, _ := http.NewRequest("GET", "https://export.arxiv.org/api/query?max_results=2&search_query=x", nil)
+
+	if FixtureKey(a) != FixtureKey(b) {
+		t.Error("Expected parameter order not to affect the fixture key")
+	}
+	if FixtureKey(a) == FixtureKey(c) {
+		t.Error("Expected different parameters to produce different fixture keys")
+	}
+}
@@ -0,0 +1,115 @@
+package arxiv
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// CircuitBreakerOptions configures the optional circuit breaker of a Client
+type CircuitBreakerOptions struct {
+	// FailureThreshold is the number of consecutive failed attempts that opens the circuit
+	FailureThreshold int
+
+	// Cooldown is how long the circuit stays open before a single probe request is allowed
+	Cooldown time.Duration
+}
+
+// circuitState represents the state of a circuit breaker
+type circuitState int
+
+const (
+	circuitClosed   circuitState = iota // Requests flow normally
+	circuitOpen                         // Requests are rejected until the cooldown ends
+	circuitHalfOpen                     // One probe request is in flight
+)
+
+// circuitBreaker rejects requests after repeated transient failures.
+// Only retryable errors (network, timeout, rate limit) count as failures;
+// any other outcome means the endpoint answered and closes the circuit.
+type circuitBreaker struct {
+	mu        sync.Mutex
+	threshold int
+	cooldown  time.Duration
+	state     circuitState
+	failures  int
+	openedAt  time.Time
+	now       func() time.Time
+}
+
+// newCircuitBreaker creates a closed circuit breaker, or returns nil if opts is nil
+func newCircuitBreaker(opts *CircuitBreakerOptions) *circuitBreaker {
+	if opts == nil {
+		return nil
+	}
+	threshold := opts.FailureThreshold
+	if threshold <= 0 {
+		threshold = 1
+	}
+	return &circuitBreaker{
+		threshold: threshold,
+		cooldown:  opts.Cooldown,
+		now:       time.Now,
+	}
+}
+
+// allow reports whether a request may be sent, moving an open circuit to
+// half-open once the cooldown has passed. A nil breaker allows everything.
+func (cb *circuitBreaker) allow() error {
+	if cb == nil {
+		return nil
+	}
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	switch cb.state {
+	case circuitOpen:
+		remaining := cb.cooldown - cb.now().Sub(cb.openedAt)
+		if remaining > 0 {
+			return NewAPIError(ErrorTypeServerError, fmt.Sprintf("circuit breaker open, retry in %v", remaining.Round(time.Millisecond)), nil)
+		}
+		cb.state = circuitHalfOpen
+		return nil
+	case circuitHalfOpen:
+		return NewAPIError(ErrorTypeServerError, "circuit breaker half-open, probe in progress", nil)
+	default:
+		return nil
+	}
+}
+
+// record updates the breaker with the outcome of an allowed request
+func (cb *circuitBreaker) record(err error) {
+	if cb == nil {
+		return
+	}
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	var apiErr *APIError
+	if err == nil || !errors.As(err, &apiErr) || !apiErr.Retry {
+		cb.state = circuitClosed
+		cb.failures = 0
+		return
+	}
+
+	cb.failures++
+	if cb.state == circuitHalfOpen || cb.failures >= cb.threshold {
+		cb.state = circuitOpen
+		cb.openedAt = cb.now()
+	}
+}
+
+// release abandons an allowed request without judging the endpoint, e.g. when
+// the caller's context was canceled, so a half-open circuit can probe again
+func (cb *circuitBreaker) release() {
+	if cb == nil {
+		return
+	}
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	if cb.state == circuitHalfOpen {
+		cb.state = circuitOpen
+	}
+}
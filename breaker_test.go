@@ -0,0 +1,128 @@
+package arxiv
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// newTestBreaker creates a circuit breaker driven by the returned fake clock
+func newTestBreaker(threshold int, cooldown time.Duration) (*circuitBreaker, *time.Time) {
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	cb := newCircuitBreaker(&CircuitBreakerOptions{FailureThreshold: threshold, Cooldown: cooldown})
+	cb.now = func() time.Time { return now }
+	return cb, &now
+}
+
+func TestCircuitBreaker_Transitions(t *testing.T) {
+	cb, now := newTestBreaker(2, time.Minute)
+	failure := NewAPIError(ErrorTypeNetwork, "down", nil)
+
+	// Closed: failures below the threshold are let through
+	if err := cb.allow(); err != nil {
+		t.Fatalf("Expected closed circuit to allow, got %v", err)
+	}
+	cb.record(failure)
+	if cb.state != circuitClosed {
+		t.Fatalf("Expected circuit to stay closed after 1 failure, got %v", cb.state)
+	}
+
+	// Open: the threshold is reached
+	cb.allow()
+	cb.record(failure)
+	if cb.state != circuitOpen {
+		t.Fatalf("Expected circuit to open after 2 failures, got %v", cb.state)
+	}
+	err := cb.allow()
+	if !errors.Is(err, ErrServerError) || IsRetryable(err) {
+		t.Fatalf("Expected non-retryable server error while open, got %v", err)
+	}
+
+	// Half-open: one probe after the cooldown
+	*now = now.Add(time.Minute)
+	if err := cb.allow(); err != nil {
+		t.Fatalf("Expected probe after cooldown, got %v", err)
+	}
+	if cb.state != circuitHalfOpen {
+		t.Fatalf("Expected half-open circuit, got %v", cb.state)
+	}
+	if err := cb.allow(); !errors.Is(err, ErrServerError) {
+		t.Errorf("Expected second request to be rejected during the probe, got %v", err)
+	}
+
+	// A failed probe reopens the circuit for another cooldown
+	cb.record(failure)
+	if cb.state != circuitOpen || cb.allow() == nil {
+		t.Fatalf("Expected failed probe to reopen the circuit, got %v", cb.state)
+	}
+
+	// A successful probe closes it
+	*now = now.Add(time.Minute)
+	cb.allow()
+	cb.record(nil)
+	if cb.state != circuitClosed || cb.allow() != nil {
+		t.Fatalf("Expected successful probe to close the circuit, got %v", cb.state)
+	}
+}
+
+func TestCircuitBreaker_NonTransientErrorsReset(t *testing.T) {
+	cb, _ := newTestBreaker(2, time.Minute)
+	failure := NewAPIError(ErrorTypeTimeout, "slow", nil)
+
+	cb.record(failure)
+	cb.record(NewAPIError(ErrorTypeNotFound, "missing", nil))
+	cb.record(failure)
+	if cb.state != circuitClosed {
+		t.Errorf("Expected a non-transient error to reset the failure count, got %v", cb.state)
+	}
+}
+
+func TestCircuitBreaker_Release(t *testing.T) {
+	cb, now := newTestBreaker(1, time.Minute)
+	cb.record(NewAPIError(ErrorTypeNetwork, "down", nil))
+
+	*now = now.Add(time.Minute)
+	cb.allow()
+	cb.release()
+	if cb.state != circuitOpen {
+		t.Fatalf("Expected released probe to leave the circuit open, got %v", cb.state)
+	}
+	if err := cb.allow(); err != nil {
+		t.Errorf("Expected a new probe after release, got %v", err)
+	}
+}
+
+func TestSearchWithCircuitBreaker(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	client := NewClientWithOptions(ClientOptions{
+		RetryAttempts:  3,
+		RetryDelay:     1 * time.Millisecond,
+		RateLimit:      1 * time.Millisecond,
+		CircuitBreaker: &CircuitBreakerOptions{FailureThreshold: 3, Cooldown: time.Hour},
+	})
+	client.baseURL = server.URL
+
+	query := &Query{SearchQuery: "test", MaxResults: 1}
+
+	_, err := client.Search(context.Background(), query)
+	if !errors.Is(err, ErrNetwork) {
+		t.Fatalf("Expected network error from the failing server, got %v", err)
+	}
+
+	_, err = client.Search(context.Background(), query)
+	if !errors.Is(err, ErrServerError) {
+		t.Fatalf("Expected open circuit to reject the request, got %v", err)
+	}
+	if requests != 3 {
+		t.Errorf("Expected the open circuit to stop requests after 3, got %d", requests)
+	}
+}
@@ -0,0 +1,173 @@
+package arxiv
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// citationStopWords are the title words skipped when picking the key's title word
+var citationStopWords = map[string]bool{
+	"a": true, "an": true, "the": true, "on": true, "of": true, "in": true, "for": true,
+	"and": true, "to": true, "with": true, "at": true, "by": true, "from": true, "is": true,
+	"are": true, "towards": true, "toward": true,
+}
+
+// nameSuffixes are the generational suffixes that don't form part of a surname
+var nameSuffixes = map[string]bool{
+	"jr": true, "jr.": true, "sr": true, "sr.": true, "ii": true, "iii": true, "iv": true,
+}
+
+// nameParticles are the words that begin family names such as "van der Waals" or "de la Cruz"
+var nameParticles = map[string]bool{
+	"van": true, "von": true, "der": true, "den": true, "de": true, "del": true, "della": true,
+	"di": true, "da": true, "du": true, "des": true, "la": true, "le": true, "dos": true,
+	"das": true, "ter": true, "ten": true, "bin": true, "ibn": true, "al": true, "el": true,
+}
+
+// transliterations maps non-ASCII letters to ASCII, derived from the LaTeX tables so
+// that "Gödel" and G\"odel give the same key: accented letters lose their accent and
+// letters such as ß or æ are spelled out as their LaTeX command names
+var transliterations = buildTransliterations()
+
+func buildTransliterations() map[rune]string {
+	table := make(map[rune]string)
+	for name, symbol := range latexSymbols {
+		r := []rune(symbol)
+		if len(r) != 1 || r[0] <= unicode.MaxASCII || !unicode.IsLetter(r[0]) {
+			continue
+		}
+		// Prefer \epsilon over \varepsilon for ε, independent of map order
+		if prev, ok := table[r[0]]; !ok || len(name) < len(prev) || len(name) == len(prev) && name < prev {
+			table[r[0]] = name
+		}
+	}
+	for command, accented := range latexAccents {
+		r := []rune(accented)
+		table[r[0]] = command[len(command)-1:]
+	}
+	return table
+}
+
+// CitationKey returns a BibTeX-style cite key made of the first author's surname, the year
+// of publication and the first significant title word, e.g. "lecun2015deep" for "Deep
+// learning" by Yann LeCun published in 2015. Accents and other non-ASCII letters are
+// transliterated or dropped. Without a usable author name the key is the paper's
+// version-less ID, e.g. "2301.00001" or "hep-th:9901001". Keys need not be unique;
+// use CitationKeys to disambiguate the keys of several papers.
+func (p *Paper) CitationKey() string {
+	surname := ""
+	if len(p.Authors) > 0 {
+		surname = citationWord(authorSurname(p.Authors[0].Name))
+	}
+	if surname == "" {
+		return strings.ReplaceAll(p.Key(), "/", ":")
+	}
+
+	key := surname
+	if !p.PublishedAt.IsZero() {
+		key += strconv.Itoa(p.PublishedAt.Year())
+	}
+	for _, word := range strings.FieldsFunc(latexToPlain(p.Title), isTitleWordSeparator) {
+		if word = citationWord(word); word != "" && !citationStopWords[word] {
+			return key + word
+		}
+	}
+	return key
+}
+
+// CitationKeys returns the cite keys of papers in order, appending "b", "c" and so on
+// to repeated keys as BibTeX styles do: the second "smith2020deep" becomes "smith2020deepb".
+// The first occurrence keeps the bare key.
+func CitationKeys(papers []Paper) []string {
+	keys := make([]string, len(papers))
+	used := make(map[string]bool, len(papers))
+	for i := range papers {
+		keys[i] = uniqueCitationKey(papers[i].CitationKey(), used)
+	}
+	return keys
+}
+
+// uniqueCitationKey returns key, suffixed if it is already in used, and marks the result as used
+func uniqueCitationKey(key string, used map[string]bool) string {
+	candidate := key
+	for n := 1; used[candidate]; n++ {
+		candidate = key + citationSuffix(n)
+	}
+	used[candidate] = true
+	return candidate
+}
+
+// citationSuffix returns the suffix of the nth repeat of a key: "b" through "z", then "ba", "bb" and so on
+func citationSuffix(n int) string {
+	var suffix []byte
+	for n > 0 {
+		suffix = append([]byte{byte('a' + n%26)}, suffix...)
+		n /= 26
+	}
+	return string(suffix)
+}
+
+// authorSurname returns the last word of the family name, e.g. "Waals" for "Johannes
+// Diderik van der Waals", so keys don't depend on how particles are written
+func authorSurname(name string) string {
+	_, family := Author{Name: name}.ParseName()
+	words := strings.Fields(family)
+	if len(words) == 0 {
+		return ""
+	}
+	return words[len(words)-1]
+}
+
+// ParseName splits the author's name into given and family names on a best-effort basis.
+// Names written "Family, Given" are split at the comma. Otherwise the last word is the
+// family name, extended backwards over particles such as "van der" or "de la" that
+// follow the first word: "Johannes Diderik van der Waals" gives "Johannes Diderik" and
+// "van der Waals". Generational suffixes such as "Jr." are dropped. A single word is
+// taken as the family name. Names from cultures that put the family name first, or
+// family names of several words without particles, are not recognized.
+func (a Author) ParseName() (given, family string) {
+	parts := strings.Split(a.Name, ",")
+	var rest []string
+	for _, part := range parts[1:] {
+		if part = strings.TrimSpace(part); part != "" && !nameSuffixes[strings.ToLower(part)] {
+			rest = append(rest, part)
+		}
+	}
+	if len(rest) > 0 {
+		return strings.Join(rest, " "), strings.Join(strings.Fields(parts[0]), " ")
+	}
+
+	words := strings.Fields(parts[0])
+	for len(words) > 1 && nameSuffixes[strings.ToLower(words[len(words)-1])] {
+		words = words[:len(words)-1]
+	}
+	if len(words) == 0 {
+		return "", ""
+	}
+
+	start := len(words) - 1
+	for start > 1 && nameParticles[strings.ToLower(words[start-1])] {
+		start--
+	}
+	return strings.Join(words[:start], " "), strings.Join(words[start:], " ")
+}
+
+// citationWord lowercases s, transliterates non-ASCII letters and drops anything that
+// isn't an ASCII letter or digit, e.g. "Gödel's" -> "godels"
+func citationWord(s string) string {
+	var b strings.Builder
+	for _, r := range latexToPlain(s) {
+		if ascii, ok := transliterations[r]; ok {
+			b.WriteString(ascii)
+		} else if r <= unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			b.WriteRune(r)
+		}
+	}
+	return strings.ToLower(b.String())
+}
+
+// isTitleWordSeparator splits titles into words on whitespace, dashes and slashes
+func isTitleWordSeparator(r rune) bool {
+	return unicode.IsSpace(r) || r == '-' || r == '–' || r == '—' || r == '/'
+}
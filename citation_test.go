@@ -0,0 +1,107 @@
+package arxiv
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestPaper_CitationKey(t *testing.T) {
+	published := time.Date(2015, 5, 27, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		paper    Paper
+		expected string
+	}{
+		{"basic", Paper{Title: "Deep learning", Authors: []Author{{Name: "Yann LeCun"}, {Name: "Yoshua Bengio"}}, PublishedAt: published}, "lecun2015deep"},
+		{"leading stop words", Paper{Title: "On the Origin of Species", Authors: []Author{{Name: "Charles Darwin"}}, PublishedAt: published}, "darwin2015origin"},
+		{"last name first", Paper{Title: "Deep learning", Authors: []Author{{Name: "LeCun, Yann"}}, PublishedAt: published}, "lecun2015deep"},
+		{"surname particles", Paper{Title: "Continuity", Authors: []Author{{Name: "Johannes Diderik van der Waals"}}, PublishedAt: published}, "waals2015continuity"},
+		{"name suffix", Paper{Title: "Letters", Authors: []Author{{Name: "Martin Luther King, Jr."}}, PublishedAt: published}, "king2015letters"},
+		{"accented surname", Paper{Title: "Über formal unentscheidbare Sätze", Authors: []Author{{Name: "Kurt Gödel"}}, PublishedAt: published}, "godel2015uber"},
+		{"latex surname", Paper{Title: "Quantum theory", Authors: []Author{{Name: `Erwin Schr\"odinger`}}, PublishedAt: published}, "schrodinger2015quantum"},
+		{"spelled out letters", Paper{Title: "Mechanics", Authors: []Author{{Name: "Hans Strauß"}}, PublishedAt: published}, "strauss2015mechanics"},
+		{"apostrophe and hyphen", Paper{Title: "Self-attention is all", Authors: []Author{{Name: "Brian O'Neil"}}, PublishedAt: published}, "oneil2015self"},
+		{"latex title", Paper{Title: `$\alpha$-stable processes`, Authors: []Author{{Name: "A. Smith"}}, PublishedAt: published}, "smith2015alpha"},
+		{"no year", Paper{Title: "Deep learning", Authors: []Author{{Name: "Yann LeCun"}}}, "lecundeep"},
+		{"no title", Paper{Authors: []Author{{Name: "Yann LeCun"}}, PublishedAt: published}, "lecun2015"},
+		{"no authors", Paper{ID: "2301.00001v2", Title: "Deep learning", PublishedAt: published}, "2301.00001"},
+		{"no authors old style", Paper{ID: "hep-th/9901001v1"}, "hep-th:9901001"},
+		{"unusable surname", Paper{ID: "2301.00002v1", Title: "Deep learning", Authors: []Author{{Name: "王小明"}}}, "2301.00002"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.paper.CitationKey(); got != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestCitationKeys(t *testing.T) {
+	published := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	paper := func(id, title string) Paper {
+		return Paper{ID: id, Title: title, Authors: []Author{{Name: "Jane Smith"}}, PublishedAt: published}
+	}
+
+	papers := []Paper{
+		paper("2001.00001", "Deep nets"),
+		paper("2001.00002", "Deep trees"),
+		paper("2001.00003", "Shallow nets"),
+		paper("2001.00004", "Deep forests"),
+		paper("2001.00005", "Deepb"),
+	}
+	expected := []string{"smith2020deep", "smith2020deepb", "smith2020shallow", "smith2020deepc", "smith2020deepbb"}
+	if got := CitationKeys(papers); !slices.Equal(got, expected) {
+		t.Errorf("Expected %v, got %v", expected, got)
+	}
+
+	if got := CitationKeys(nil); len(got) != 0 {
+		t.Errorf("Expected no keys, got %v", got)
+	}
+}
+
+func TestCitationSuffix(t *testing.T) {
+	for n, expected := range map[int]string{1: "b", 2: "c", 25: "z", 26: "ba", 27: "bb"} {
+		if got := citationSuffix(n); got != expected {
+			t.Errorf("citationSuffix(%d): expected %q, got %q", n, expected, got)
+		}
+	}
+}
+
+func TestAuthor_ParseName(t *testing.T) {
+	tests := []struct {
+		name   string
+		given  string
+		family string
+	}{
+		{"Yann LeCun", "Yann", "LeCun"},
+		{"Geoffrey E. Hinton", "Geoffrey E.", "Hinton"},
+		{"  Ada   Lovelace ", "Ada", "Lovelace"},
+		{"Jean-Pierre Serre", "Jean-Pierre", "Serre"},
+		{"Plato", "", "Plato"},
+		{"", "", ""},
+		{"Ludwig van Beethoven", "Ludwig", "van Beethoven"},
+		{"Johannes Diderik van der Waals", "Johannes Diderik", "van der Waals"},
+		{"Juana Inés de la Cruz", "Juana Inés", "de la Cruz"},
+		{"Leonardo Da Vinci", "Leonardo", "Da Vinci"},
+		{"Van Morrison", "Van", "Morrison"},
+		{"Martin Luther King Jr.", "Martin Luther", "King"},
+		{"Martin Luther King, Jr.", "Martin Luther", "King"},
+		{"LeCun, Yann", "Yann", "LeCun"},
+		{"van der Waals, Johannes Diderik", "Johannes Diderik", "van der Waals"},
+		{"King, Martin Luther, Jr.", "Martin Luther", "King"},
+		{"King, Jr., Martin Luther", "Martin Luther", "King"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			given, family := Author{Name: tt.name}.ParseName()
+			if given != tt.given || family != tt.family {
+				t.Errorf("ParseName(%q): expected (%q, %q), got (%q, %q)", tt.name, tt.given, tt.family, given, family)
+			}
+		})
+	}
+}
@@ -1,15 +1,20 @@
 package arxiv
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"net/url"
+	"os"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -19,6 +24,8 @@ const (
 
 	// Default values
 	defaultMaxResults = 500
+	maxResultsLimit   = 30000 // arXiv refuses larger max_results values
+	maxIDBatchSize    = 2000  // arXiv returns at most 2000 entries per request
 	defaultLimit      = 0
 	defaultSortBy     = "relevance"
 	defaultSortOrder  = "descending"
@@ -28,8 +35,37 @@ const (
 	defaultRateLimit     = 1000 * time.Millisecond
 	defaultUserAgent     = "arxiv-go/1.0"
 	defaultTimeout       = 30 * time.Second
+
+	defaultMaxResponseBytes = 64 << 20
+	maxErrorBodyBytes       = 64 << 10 // Read from error responses for their explanation
+
+	// Polite mode follows arXiv's guidance of one request every 3 seconds
+	politeRateLimit     = 3 * time.Second
+	politeRetryAttempts = 3
+	politeRetryDelay    = 10 * time.Second
+
+	// Response buffers larger than this are not returned to the pool
+	maxPooledBufferSize = 16 << 20
+
+	// Header carrying the caller's correlation ID
+	requestIDHeader = "X-Request-ID"
 )
 
+// requestIDKey is the context key for the per-request correlation ID
+type requestIDKey struct{}
+
+// WithRequestID returns a copy of ctx carrying a correlation ID that is sent
+// with every API request made using the returned context
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestIDFromContext returns the correlation ID stored in ctx, if any
+func RequestIDFromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(requestIDKey{}).(string)
+	return id, ok && id != ""
+}
+
 // ClientOptions represents configuration options for the arXiv client
 type ClientOptions struct {
 
@@ -39,27 +75,107 @@ type ClientOptions struct {
 	// RetryDelay specifies the initial delay between retry attempts
 	RetryDelay time.Duration
 
+	// MaxElapsedRetryTime caps the total time spent retrying a request (0 = unlimited).
+	// No further attempt starts once the elapsed time plus the next retry delay would
+	// exceed it; the last error is returned instead. It does not cut short an attempt
+	// in flight, so the worst case is about MaxElapsedRetryTime plus one Timeout.
+	MaxElapsedRetryTime time.Duration
+
 	// RateLimit specifies the minimum delay between requests
 	RateLimit time.Duration
 
 	// UserAgent specifies the User-Agent header to use
 	UserAgent string
 
-	// Timeout specifies the request timeout
+	// Timeout specifies the request timeout. The context deadline and Query.Timeout
+	// also apply to each request; the most restrictive wins.
 	Timeout time.Duration
+
+	// DefaultMaxResults specifies the max_results sent for queries that don't set MaxResults.
+	// NewClientWithOptions replaces values below 1 with the package default and clamps values
+	// above arXiv's limit of 30000; Validate reports them instead.
+	DefaultMaxResults int
+
+	// IDBatchSize specifies how many IDs are sent in each id_list request by GetByIDs
+	// and iterators (default 100). arXiv answers at most 2000 entries per request and
+	// very long id_list URLs may be rejected, so larger values are clamped to 2000.
+	IDBatchSize int
+
+	// MaxResponseBytes caps the size of a response body (default 64MB, negative = unlimited).
+	// Larger responses fail with an ErrorTypeParsing error wrapping ErrResponseTooLarge.
+	MaxResponseBytes int64
+
+	// Proxy routes requests through the given HTTP(S) or SOCKS5 proxy.
+	// When nil, the proxy is taken from the environment (HTTP_PROXY etc.).
+	// An invalid URL makes every request fail with an ErrorTypeNetwork error.
+	Proxy *url.URL
+
+	// CircuitBreaker, when set, rejects requests with an ErrorTypeServerError for a cooldown
+	// period after FailureThreshold consecutive transient failures, then allows one probe
+	CircuitBreaker *CircuitBreakerOptions
+
+	// Debug writes the URL of every request, with its WithRequestID correlation ID, and the
+	// raw response body, unredacted, to DebugWriter. It is meant for one-off investigations
+	// and can produce a lot of output.
+	Debug bool
+
+	// DebugWriter receives the Debug output (default os.Stderr)
+	DebugWriter io.Writer
+
+	// RetryNotFound retries ErrorTypeNotFound errors like transient ones, with the same
+	// RetryAttempts and RetryDelay. Papers can take a while to become searchable after
+	// they are announced, so pipelines that fetch them right away may see GetByID fail
+	// at first. It applies to GetByID and to queries with ErrorOnEmpty; it is off by
+	// default so that unknown IDs fail fast. The errors still report Retry as false.
+	RetryNotFound bool
+
+	// Singleflight makes concurrent Searches for the same query, as identified by
+	// Query.CacheKey, share a single request using golang.org/x/sync/singleflight. Each
+	// caller gets its own deep copy of the results, or of the *APIError. The shared request
+	// uses the first caller's Query.Timeout and runs to completion even if every caller
+	// stops waiting for it.
+	Singleflight bool
+
+	// PreserveWhitespace keeps titles and abstracts as arXiv sends them. By default runs of
+	// whitespace, including the line breaks arXiv embeds in the feed, collapse to single spaces.
+	PreserveWhitespace bool
 }
 
 // DefaultClientOptions returns the default client options
 func DefaultClientOptions() ClientOptions {
 	return ClientOptions{
-		RetryAttempts: defaultRetryAttempts,
-		RetryDelay:    defaultRetryDelay,
-		RateLimit:     defaultRateLimit,
-		UserAgent:     defaultUserAgent,
-		Timeout:       defaultTimeout,
+		RetryAttempts:     defaultRetryAttempts,
+		RetryDelay:        defaultRetryDelay,
+		RateLimit:         defaultRateLimit,
+		UserAgent:         defaultUserAgent,
+		Timeout:           defaultTimeout,
+		DefaultMaxResults: defaultMaxResults,
+		IDBatchSize:       defaultIDBatchSize,
+		MaxResponseBytes:  defaultMaxResponseBytes,
 	}
 }
 
+// PoliteClientOptions returns the default options adjusted to arXiv's API usage guidance
+// for bulk use: at most one request every 3 seconds and retries spaced 10 seconds apart.
+// Every request waits up to 3 seconds, so prefer DefaultClientOptions for interactive
+// use and this for large harvests.
+func PoliteClientOptions() ClientOptions {
+	opts := DefaultClientOptions()
+	opts.RateLimit = politeRateLimit
+	opts.RetryAttempts = politeRetryAttempts
+	opts.RetryDelay = politeRetryDelay
+	return opts
+}
+
+// Searcher is the subset of the client used by QueryBuilder and Iterator.
+// *Client implements it; tests can substitute a fake such as arxivtest.FakeClient.
+type Searcher interface {
+	Search(ctx context.Context, query *Query) (*SearchResults, error)
+	GetByID(ctx context.Context, id string) (*Paper, error)
+}
+
+var _ Searcher = (*Client)(nil)
+
 // Client represents an arXiv API client
 type Client struct {
 	httpClient  *http.Client
@@ -67,7 +183,58 @@ type Client struct {
 	options     ClientOptions
 	lastRequest time.Time
 
-	rlMu sync.Mutex // Mutex for rate limiting
+	rlMu    sync.Mutex      // Mutex for rate limiting
+	debugMu sync.Mutex      // Serializes debug dumps
+	bufPool sync.Pool       // Reusable response body buffers
+	stats   clientStats     // Cumulative request counters
+	breaker *circuitBreaker // Optional; nil when disabled
+	flights *searchFlights  // Optional; nil unless Singleflight is set
+}
+
+// ClientStats is a snapshot of a client's cumulative request counters
+type ClientStats struct {
+	Requests      int64               // HTTP requests sent, including retries
+	Retries       int64               // Attempts repeated after a retryable error
+	RateLimitHits int64               // Responses with status 429 or 503
+	BytesRead     int64               // Response body bytes read
+	Errors        map[ErrorType]int64 // Failed attempts by error type
+}
+
+// clientStats holds the atomic counters behind Client.Stats
+type clientStats struct {
+	requests      atomic.Int64
+	retries       atomic.Int64
+	rateLimitHits atomic.Int64
+	bytesRead     atomic.Int64
+	errors        [numErrorTypes]atomic.Int64
+}
+
+// recordError counts a failed attempt under its error type
+func (s *clientStats) recordError(err error) {
+	errorType := ErrorTypeUnknown
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.Type >= 0 && apiErr.Type < numErrorTypes {
+		errorType = apiErr.Type
+	}
+	s.errors[errorType].Add(1)
+}
+
+// Stats returns a snapshot of the client's cumulative request counters.
+// It is safe to call concurrently with requests.
+func (c *Client) Stats() ClientStats {
+	stats := ClientStats{
+		Requests:      c.stats.requests.Load(),
+		Retries:       c.stats.retries.Load(),
+		RateLimitHits: c.stats.rateLimitHits.Load(),
+		BytesRead:     c.stats.bytesRead.Load(),
+		Errors:        make(map[ErrorType]int64),
+	}
+	for errorType := range c.stats.errors {
+		if n := c.stats.errors[errorType].Load(); n > 0 {
+			stats.Errors[ErrorType(errorType)] = n
+		}
+	}
+	return stats
 }
 
 // NewClient creates a new arXiv API client
@@ -75,7 +242,46 @@ func NewClient() *Client {
 	return NewClientWithOptions(DefaultClientOptions())
 }
 
-// NewClientWithHTTPClient creates a new arXiv API client with custom HTTP client
+var (
+	defaultClient     atomic.Pointer[Client]
+	defaultClientOnce sync.Once
+)
+
+// getDefaultClient returns the client used by the package-level functions,
+// creating it with default options on first use
+func getDefaultClient() *Client {
+	defaultClientOnce.Do(func() {
+		defaultClient.CompareAndSwap(nil, NewClient())
+	})
+	return defaultClient.Load()
+}
+
+// SetDefaultClient replaces the client used by the package-level Search and GetByID.
+// Passing nil restores a client with default options.
+func SetDefaultClient(c *Client) {
+	if c == nil {
+		c = NewClient()
+	}
+	defaultClient.Store(c)
+}
+
+// Search runs query with the default client. See Client.Search.
+func Search(ctx context.Context, query *Query) (*SearchResults, error) {
+	return getDefaultClient().Search(ctx, query)
+}
+
+// GetByID fetches a paper by its arXiv ID with the default client. See Client.GetByID.
+func GetByID(ctx context.Context, id string) (*Paper, error) {
+	return getDefaultClient().GetByID(ctx, id)
+}
+
+// NewPoliteClient creates a new arXiv API client with PoliteClientOptions
+func NewPoliteClient() *Client {
+	return NewClientWithOptions(PoliteClientOptions())
+}
+
+// NewClientWithHTTPClient creates a new arXiv API client with custom HTTP client and the
+// default options. Use NewClientWithOptionsAndHTTPClient to customize both.
 func NewClientWithHTTPClient(httpClient *http.Client) *Client {
 	opts := DefaultClientOptions()
 	return &Client{
@@ -86,7 +292,17 @@ func NewClientWithHTTPClient(httpClient *http.Client) *Client {
 	}
 }
 
-// NewClientWithOptions creates a new arXiv API client with custom options
+// Validate reports options that NewClientWithOptions would silently replace or clamp,
+// currently a negative DefaultMaxResults or one above arXiv's limit
+func (o ClientOptions) Validate() error {
+	if o.DefaultMaxResults < 0 || o.DefaultMaxResults > maxResultsLimit {
+		return NewAPIError(ErrorTypeInvalidQuery, fmt.Sprintf("invalid DefaultMaxResults %d: must be between 1 and %d, or 0 for the default", o.DefaultMaxResults, maxResultsLimit), nil)
+	}
+	return nil
+}
+
+// NewClientWithOptions creates a new arXiv API client with custom options. Out-of-range
+// values are replaced or clamped as each option documents; call Validate first to catch them.
 // TODO: do not use magic values for defaults, use constants or config
 func NewClientWithOptions(opts ClientOptions) *Client {
 	// Set defaults for zero values
@@ -105,84 +321,354 @@ func NewClientWithOptions(opts ClientOptions) *Client {
 	if opts.Timeout == 0 {
 		opts.Timeout = defaultTimeout
 	}
+	if opts.DefaultMaxResults <= 0 {
+		opts.DefaultMaxResults = defaultMaxResults
+	} else if opts.DefaultMaxResults > maxResultsLimit {
+		opts.DefaultMaxResults = maxResultsLimit
+	}
+	if opts.IDBatchSize <= 0 {
+		opts.IDBatchSize = defaultIDBatchSize
+	} else if opts.IDBatchSize > maxIDBatchSize {
+		opts.IDBatchSize = maxIDBatchSize
+	}
+	if opts.MaxResponseBytes == 0 {
+		opts.MaxResponseBytes = defaultMaxResponseBytes
+	}
+
+	httpClient := &http.Client{
+		Timeout: opts.Timeout,
+	}
+	if opts.Proxy != nil {
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.Proxy = proxyFunc(opts.Proxy)
+		httpClient.Transport = transport
+	}
 
 	return &Client{
-		httpClient: &http.Client{
-			Timeout: opts.Timeout,
-		},
+		httpClient:  httpClient,
 		baseURL:     baseURL,
 		options:     opts,
 		lastRequest: time.Time{},
+		breaker:     newCircuitBreaker(opts.CircuitBreaker),
+		flights:     newSearchFlights(opts.Singleflight),
 	}
 }
 
-// Search searches for papers using the arXiv API with retry and rate limiting
+// NewClientWithOptionsAndHTTPClient creates a new arXiv API client with custom options that
+// sends its requests through httpClient, e.g. one with an instrumented Transport.
+// Everything the client does around a request follows opts: retries, rate limiting, the
+// User-Agent, response limits and the circuit breaker. The HTTP client's own settings
+// win where they are set: its Transport, redirect policy, cookie jar and a non-zero
+// Timeout; opts.Timeout applies when its Timeout is zero. opts.Proxy replaces the proxy
+// of an *http.Transport, or of the default one when Transport is nil, and is ignored for
+// other RoundTripper implementations. httpClient itself is not modified; nil behaves
+// like NewClientWithOptions.
+func NewClientWithOptionsAndHTTPClient(opts ClientOptions, httpClient *http.Client) *Client {
+	c := NewClientWithOptions(opts)
+	if httpClient == nil {
+		return c
+	}
+
+	hc := *httpClient
+	if hc.Timeout == 0 {
+		hc.Timeout = c.options.Timeout
+	}
+	if c.options.Proxy != nil {
+		var transport *http.Transport
+		switch t := hc.Transport.(type) {
+		case nil:
+			transport = http.DefaultTransport.(*http.Transport).Clone()
+		case *http.Transport:
+			transport = t.Clone()
+		}
+		if transport != nil {
+			transport.Proxy = proxyFunc(c.options.Proxy)
+			hc.Transport = transport
+		}
+	}
+	c.httpClient = &hc
+	return c
+}
+
+// proxyFunc returns a Transport.Proxy function that always uses proxyURL,
+// or fails every request if proxyURL is not a usable proxy address
+func proxyFunc(proxyURL *url.URL) func(*http.Request) (*url.URL, error) {
+	var err error
+	switch {
+	case proxyURL.Scheme != "http" && proxyURL.Scheme != "https" && proxyURL.Scheme != "socks5":
+		err = fmt.Errorf("invalid proxy URL %q: unsupported scheme %q", proxyURL.Redacted(), proxyURL.Scheme)
+	case proxyURL.Host == "":
+		err = fmt.Errorf("invalid proxy URL %q: missing host", proxyURL.Redacted())
+	}
+
+	return func(*http.Request) (*url.URL, error) {
+		if err != nil {
+			return nil, err
+		}
+		return proxyURL, nil
+	}
+}
+
+// Search searches for papers using the arXiv API with retry and rate limiting.
+// A query matching nothing is not an error: the results are empty (see SearchResults.IsEmpty)
+// unless query.ErrorOnEmpty is set, in which case Search fails with ErrorTypeNotFound like GetByID.
+// Errors are always *APIError; if ctx is done, the error has type ErrorTypeTimeout and wraps ctx.Err().
 func (c *Client) Search(ctx context.Context, query *Query) (*SearchResults, error) {
 	if query == nil {
 		return nil, NewAPIError(ErrorTypeInvalidQuery, "query cannot be nil", nil)
 	}
+	if err := query.Validate(); err != nil {
+		return nil, err
+	}
+
+	if c.flights == nil {
+		return c.search(ctx, query)
+	}
+	return c.flights.do(ctx, query.CacheKey(), func(ctx context.Context) (*SearchResults, error) {
+		return c.search(ctx, query)
+	})
+}
 
+// search sends the request for a validated query, retrying as configured
+func (c *Client) search(ctx context.Context, query *Query) (*SearchResults, error) {
 	var result *SearchResults
 	err := c.retryWithBackoff(ctx, func() error {
-		// Build URL
-		params := c.buildQueryParams(query)
-		reqURL := fmt.Sprintf("%s?%s", c.baseURL, params.Encode())
+		return c.get(ctx, c.queryURL(query), query.Timeout, func(body []byte) error {
+			// Parse XML response
+			// TODO: implement ErrorTypeNoEntry retry
+			parsedResult, err := c.parseSearchResponse(body, query.SkipAbstract)
+			if err != nil {
+				return newParseError("failed to parse response", err)
+			}
+			// Checked per attempt so that ClientOptions.RetryNotFound can retry it
+			if query.ErrorOnEmpty && parsedResult.TotalCount == 0 && len(parsedResult.Papers) == 0 {
+				return NewAPIError(ErrorTypeNotFound, "no papers match the query", nil)
+			}
 
-		// Create HTTP request
-		req, err := http.NewRequestWithContext(ctx, "GET", reqURL, nil)
-		if err != nil {
-			return NewAPIError(ErrorTypeNetwork, "failed to create request", err)
+			result = parsedResult
+			return nil
+		})
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	result.SortBy = query.SortBy
+	result.SortOrder = query.SortOrder
+
+	// Entry order encodes relevance when results are sorted by it
+	if result.SortBy == "" || result.SortBy == string(SortByRelevance) {
+		for i := range result.Papers {
+			result.Papers[i].relevanceRank = result.StartIndex + i + 1
 		}
+	}
+
+	if query.StrictDateFilter {
+		filterDateRange(result, query.SubmittedDateFrom, query.SubmittedDateTo)
+	}
+	if query.PrimaryCategory != "" {
+		filterPapers(result, func(paper *Paper) bool {
+			return paper.PrimaryCategory() == query.PrimaryCategory
+		})
+	}
+	return result, nil
+}
 
-		userAgent := c.options.UserAgent
-		if userAgent == "" {
-			userAgent = defaultUserAgent
+// QueryURL returns the arXiv API URL that Search requests for query, without making the
+// request, for logging and reproducing queries. Paging through an Iterator requests the
+// same URL with a different start parameter for each page.
+func (c *Client) QueryURL(query *Query) (string, error) {
+	if query == nil {
+		return "", NewAPIError(ErrorTypeInvalidQuery, "query cannot be nil", nil)
+	}
+	if err := query.Validate(); err != nil {
+		return "", err
+	}
+	return c.queryURL(query), nil
+}
+
+// queryURL builds the API request URL for a validated query
+func (c *Client) queryURL(query *Query) string {
+	return fmt.Sprintf("%s?%s", c.baseURL, c.buildQueryParams(query).Encode())
+}
+
+// pingQuery is the minimal search Ping sends
+var pingQuery = Query{SearchQuery: "all:electron", MaxResults: 1}
+
+// Ping checks that arXiv is reachable with the client's configuration by sending a
+// one-result search and checking that the response is an arXiv feed. It waits for the
+// rate limiter like any request but makes a single attempt, so an outage fails fast;
+// the error is an *APIError as for Search.
+func (c *Client) Ping(ctx context.Context) error {
+	err := c.get(ctx, c.queryURL(&pingQuery), 0, func(body []byte) error {
+		if _, err := c.parseSearchResponse(body, true); err != nil {
+			return newParseError("unexpected ping response", err)
 		}
-		req.Header.Set("User-Agent", userAgent)
+		return nil
+	})
+	if err != nil && ctx.Err() != nil {
+		return newContextError(ctx)
+	}
+	return err
+}
 
-		// Apply rate limiting and update last request time
-		err = c.applyRateLimit(ctx)
-		if err != nil {
-			return err
+// filterDateRange drops the papers of results published outside [from, to]; nil bounds are open
+func filterDateRange(results *SearchResults, from, to *time.Time) {
+	filterPapers(results, func(paper *Paper) bool {
+		return (from == nil || !paper.PublishedAt.Before(*from)) && (to == nil || !paper.PublishedAt.After(*to))
+	})
+}
+
+// filterPapers drops the papers of results for which keep returns false, counting them as filtered
+func filterPapers(results *SearchResults, keep func(*Paper) bool) {
+	kept := results.Papers[:0]
+	for i := range results.Papers {
+		if !keep(&results.Papers[i]) {
+			results.filtered++
+			continue
 		}
+		kept = append(kept, results.Papers[i])
+	}
+	results.Papers = kept
+}
 
-		// Make request
-		resp, err := c.httpClient.Do(req)
-		if err != nil {
-			return NewAPIError(ErrorTypeNetwork, "failed to make request", err)
+// get performs a single rate-limited GET request and passes the response body to handle.
+// A positive timeout bounds the HTTP request itself, excluding the rate limit wait.
+// The body is only valid for the duration of the handle call.
+func (c *Client) get(ctx context.Context, reqURL string, timeout time.Duration, handle func(body []byte) error) error {
+	// Apply rate limiting and update last request time
+	err := c.applyRateLimit(ctx)
+	if err != nil {
+		return err
+	}
+
+	reqCtx := ctx
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		reqCtx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	// Create HTTP request
+	req, err := http.NewRequestWithContext(reqCtx, "GET", reqURL, nil)
+	if err != nil {
+		return NewAPIError(ErrorTypeNetwork, "failed to create request", err)
+	}
+
+	userAgent := c.options.UserAgent
+	if userAgent == "" {
+		userAgent = defaultUserAgent
+	}
+	req.Header.Set("User-Agent", userAgent)
+	requestID, _ := RequestIDFromContext(ctx)
+	if requestID != "" {
+		req.Header.Set(requestIDHeader, requestID)
+	}
+
+	// Make request
+	c.stats.requests.Add(1)
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		c.debugDump(reqURL, requestID, "error: "+err.Error(), nil)
+		// Transports may report their own failures as an APIError, e.g. arxivtest's missing fixtures
+		var apiErr *APIError
+		if errors.As(err, &apiErr) {
+			return apiErr
 		}
-		defer resp.Body.Close()
+		if isTimeoutError(err) {
+			return NewAPIError(ErrorTypeTimeout, "request timed out", err)
+		}
+		return NewAPIError(ErrorTypeNetwork, "failed to make request", err)
+	}
+	defer resp.Body.Close()
 
-		switch resp.StatusCode {
-		case http.StatusOK:
-			// Continue
-		case http.StatusTooManyRequests, http.StatusServiceUnavailable:
-			return NewAPIError(ErrorTypeRateLimit, "rate limit exceeded", fmt.Errorf("rate limit exceeded, status %d", resp.StatusCode))
-		default:
-			return NewAPIError(ErrorTypeNetwork, "API error", fmt.Errorf("unexpected status code %d", resp.StatusCode))
+	// Error bodies are only read for arXiv's explanation of a bad request, or to dump them
+	var errorBody []byte
+	if resp.StatusCode != http.StatusOK {
+		if resp.StatusCode == http.StatusBadRequest || c.options.Debug {
+			errorBody, _ = io.ReadAll(io.LimitReader(resp.Body, maxErrorBodyBytes))
 		}
+		c.debugDump(reqURL, requestID, resp.Status, errorBody)
+	}
 
-		// Read response body
-		body, err := io.ReadAll(resp.Body)
-		if err != nil {
-			return NewAPIError(ErrorTypeNetwork, "failed to read response body", err)
+	switch resp.StatusCode {
+	case http.StatusOK:
+		// Continue
+	case http.StatusBadRequest:
+		// arXiv explains what is wrong with the query in the body
+		message := "invalid query"
+		if explanation := parseErrorExplanation(errorBody); explanation != "" {
+			message += ": " + explanation
 		}
+		return NewAPIError(ErrorTypeInvalidQuery, message, fmt.Errorf("unexpected status code %d", resp.StatusCode))
+	case http.StatusTooManyRequests, http.StatusServiceUnavailable:
+		c.stats.rateLimitHits.Add(1)
+		return NewAPIError(ErrorTypeRateLimit, "rate limit exceeded", fmt.Errorf("rate limit exceeded, status %d", resp.StatusCode))
+	default:
+		return NewAPIError(ErrorTypeNetwork, "API error", fmt.Errorf("unexpected status code %d", resp.StatusCode))
+	}
 
-		// Parse XML response
-		// TODO: implement ErrorTypeNoEntry retry
-		parsedResult, err := c.parseSearchResponse(body)
-		if err != nil {
-			return NewAPIError(ErrorTypeParsing, "failed to parse response", err)
+	// Read response body into a pooled buffer, reading one byte past the cap to detect overflow
+	var body io.Reader = resp.Body
+	limit := c.options.MaxResponseBytes
+	if limit > 0 {
+		body = io.LimitReader(resp.Body, limit+1)
+	}
+	buf := c.getBuffer()
+	defer c.putBuffer(buf)
+	n, err := buf.ReadFrom(body)
+	c.stats.bytesRead.Add(n)
+	if err != nil {
+		c.debugDump(reqURL, requestID, fmt.Sprintf("%s, error reading body: %v", resp.Status, err), buf.Bytes())
+		if isTimeoutError(err) {
+			return NewAPIError(ErrorTypeTimeout, "timed out reading response body", err)
 		}
+		return NewAPIError(ErrorTypeNetwork, "failed to read response body", err)
+	}
+	c.debugDump(reqURL, requestID, resp.Status, buf.Bytes())
+	if limit > 0 && int64(buf.Len()) > limit {
+		return NewAPIError(ErrorTypeParsing, fmt.Sprintf("response body exceeds %d bytes", limit), ErrResponseTooLarge)
+	}
 
-		result = parsedResult
-		return nil
-	})
+	return handle(buf.Bytes())
+}
 
-	if err != nil {
-		return nil, err
+// debugDump writes a request URL, its correlation ID if any, the outcome and the raw response
+// body to the debug writer when ClientOptions.Debug is set. Dumps of concurrent requests
+// don't interleave.
+func (c *Client) debugDump(reqURL, requestID, outcome string, body []byte) {
+	if !c.options.Debug {
+		return
+	}
+	w := c.options.DebugWriter
+	if w == nil {
+		w = os.Stderr
+	}
+
+	c.debugMu.Lock()
+	defer c.debugMu.Unlock()
+	if requestID != "" {
+		fmt.Fprintf(w, "arxiv: GET %s (request ID %s)\narxiv: %s\n", reqURL, requestID, outcome)
+	} else {
+		fmt.Fprintf(w, "arxiv: GET %s\narxiv: %s\n", reqURL, outcome)
+	}
+	if len(body) > 0 {
+		w.Write(body)
+		if body[len(body)-1] != '\n' {
+			io.WriteString(w, "\n")
+		}
 	}
-	return result, nil
+}
+
+// isTimeoutError reports whether err was caused by a deadline, as opposed to a
+// connection or DNS failure
+func isTimeoutError(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
 }
 
 // GetByID retrieves a paper by its arXiv ID with retry logic
@@ -192,11 +678,19 @@ func (c *Client) GetByID(ctx context.Context, id string) (*Paper, error) {
 	}
 
 	query := &Query{
-		IDList:     []string{id},
-		MaxResults: 1,
+		IDList:       []string{id},
+		MaxResults:   1,
+		ErrorOnEmpty: true,
 	}
 
 	results, err := c.Search(ctx, query)
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.Type == ErrorTypeNotFound {
+		// The error may be shared with other callers, so name the ID in a copy
+		notFound := *apiErr
+		notFound.Message = fmt.Sprintf("paper with ID %s not found", id)
+		return nil, &notFound
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -208,15 +702,224 @@ func (c *Client) GetByID(ctx context.Context, id string) (*Paper, error) {
 	return &results.Papers[0], nil
 }
 
+// GetLatestVersion retrieves the current version of a paper. Any version suffix on
+// baseID is ignored, and the returned Paper's ID carries the latest version.
+// Listing every version requires arXiv's OAI-PMH interface.
+func (c *Client) GetLatestVersion(ctx context.Context, baseID string) (*Paper, error) {
+	return c.GetByID(ctx, BaseID(baseID))
+}
+
+// GetVersionsMap retrieves the given versions of a paper in one request and returns them
+// keyed by version number, e.g. GetVersionsMap(ctx, "2301.00001", 1, 2). Any version
+// suffix on baseID is ignored. Versions that don't exist map to nil.
+func (c *Client) GetVersionsMap(ctx context.Context, baseID string, versions ...int) (map[int]*Paper, error) {
+	if baseID == "" {
+		return nil, NewAPIError(ErrorTypeInvalidQuery, "id cannot be empty", nil)
+	}
+	if len(versions) == 0 {
+		return nil, NewAPIError(ErrorTypeInvalidQuery, "versions cannot be empty", nil)
+	}
+
+	papers := make(map[int]*Paper, len(versions))
+	ids := make([]string, 0, len(versions))
+	for _, version := range versions {
+		if version < 1 {
+			return nil, NewAPIError(ErrorTypeInvalidQuery, fmt.Sprintf("invalid version %d: must be at least 1", version), nil)
+		}
+		if _, ok := papers[version]; !ok {
+			papers[version] = nil
+			ids = append(ids, BaseID(baseID)+"v"+strconv.Itoa(version))
+		}
+	}
+
+	results, err := c.Search(ctx, &Query{IDList: ids, MaxResults: len(ids)})
+	if err != nil {
+		return nil, err
+	}
+	for i := range results.Papers {
+		suffix := versionSuffixPattern.FindString(results.Papers[i].ID)
+		if version := parseIDVersion(strings.TrimPrefix(suffix, "v")); version > 0 {
+			if _, requested := papers[version]; requested {
+				papers[version] = &results.Papers[i]
+			}
+		}
+	}
+	return papers, nil
+}
+
+// GetByIDs retrieves several papers by arXiv ID, sending IDBatchSize IDs per request.
+// IDs that arXiv doesn't know are omitted from the result.
+func (c *Client) GetByIDs(ctx context.Context, ids []string) ([]*Paper, error) {
+	if len(ids) == 0 {
+		return nil, NewAPIError(ErrorTypeInvalidQuery, "ids cannot be empty", nil)
+	}
+
+	query := &Query{IDList: ids}
+	return c.Iterator(ctx, query).Collect()
+}
+
+// GetByIDsMatching retrieves papers by arXiv ID like GetByIDs and keeps those for which
+// pred returns true, e.g. GetByIDsMatching(ctx, ids, HasCategory(CategoryCSLG)). arXiv
+// doesn't reliably apply a search_query together with an id_list, so every ID is fetched
+// and pred is applied client-side as the batches arrive. A nil pred keeps every paper.
+func (c *Client) GetByIDsMatching(ctx context.Context, ids []string, pred func(*Paper) bool) ([]*Paper, error) {
+	if len(ids) == 0 {
+		return nil, NewAPIError(ErrorTypeInvalidQuery, "ids cannot be empty", nil)
+	}
+
+	var papers []*Paper
+	it := c.Iterator(ctx, &Query{IDList: ids})
+	for paper := range it.All() {
+		if pred == nil || pred(paper) {
+			papers = append(papers, paper)
+		}
+	}
+	if err := it.Error(); err != nil {
+		return nil, err
+	}
+	return papers, nil
+}
+
+// GetByIDsConcurrent retrieves papers by arXiv ID like GetByIDs, but sends the batches of
+// IDBatchSize IDs with up to workers concurrent requests, all subject to the client's rate
+// limit. The result has one entry per ID in the order given, matched by version-less ID;
+// entries for IDs that arXiv doesn't know are nil. On error the entries of batches that
+// failed or weren't fetched are nil too.
+func (c *Client) GetByIDsConcurrent(ctx context.Context, ids []string, workers int) ([]*Paper, error) {
+	if len(ids) == 0 {
+		return nil, NewAPIError(ErrorTypeInvalidQuery, "ids cannot be empty", nil)
+	}
+
+	batchSize := c.idBatchSize()
+	var batches []Query
+	for batch := range slices.Chunk(ids, batchSize) {
+		batches = append(batches, Query{IDList: batch, MaxResults: len(batch)})
+	}
+
+	batchResults, _, err := fetchPages(ctx, c, batches, max(workers, 1))
+
+	papers := make([]*Paper, len(ids))
+	for b, results := range batchResults {
+		if results == nil {
+			continue
+		}
+		byKey := make(map[string]*Paper, len(results.Papers))
+		for i := range results.Papers {
+			byKey[results.Papers[i].Key()] = &results.Papers[i]
+		}
+		for i, id := range batches[b].IDList {
+			papers[b*batchSize+i] = byKey[BaseID(id)]
+		}
+	}
+	return papers, err
+}
+
+// SearchSince returns papers matching query that were submitted strictly after since,
+// newest first. Results are sorted by submittedDate descending and paging stops at the
+// first paper published at or before since, so earlier history is never fetched.
+func (c *Client) SearchSince(ctx context.Context, query *Query, since time.Time) ([]*Paper, error) {
+	if query == nil {
+		return nil, NewAPIError(ErrorTypeInvalidQuery, "query cannot be nil", nil)
+	}
+
+	sinceQuery := *query
+	sinceQuery.SortBy = string(SortBySubmittedDate)
+	sinceQuery.SortOrder = string(SortOrderDescending)
+
+	var papers []*Paper
+	it := c.Iterator(ctx, &sinceQuery)
+	for paper := range it.All() {
+		if !paper.PublishedAt.After(since) {
+			break
+		}
+		papers = append(papers, paper)
+	}
+	if err := it.Error(); err != nil {
+		return nil, err
+	}
+	return papers, nil
+}
+
+// ListRecent returns the papers in cat submitted within the given duration before now,
+// newest first, e.g. ListRecent(ctx, CategoryCSLG, 24*time.Hour) for the last day.
+// Papers count by their first submission time, Paper.PublishedAt. arXiv only shows a
+// paper once it is announced, which Paper.AnnouncementDate estimates: submissions made
+// before the 14:00 US Eastern cutoff are announced that evening, except that Friday's
+// batch goes out on Sunday and weekend submissions on Monday. A window shorter than the
+// time since the last cutoff therefore misses papers not yet announced; use at least
+// 72 hours to cover a weekend.
+func (c *Client) ListRecent(ctx context.Context, cat Category, within time.Duration) ([]*Paper, error) {
+	if cat == "" {
+		return nil, NewAPIError(ErrorTypeInvalidQuery, "category cannot be empty", nil)
+	}
+	if within <= 0 {
+		return nil, NewAPIError(ErrorTypeInvalidQuery, fmt.Sprintf("invalid window %v: must be positive", within), nil)
+	}
+
+	since := time.Now().Add(-within)
+	// arXiv matches submittedDate by UTC day; a local date could be a day later and skip
+	// papers. SearchSince trims the results to the exact instant.
+	from := since.UTC()
+	query := &Query{
+		SearchQuery:       "cat:" + string(cat),
+		SubmittedDateFrom: &from,
+	}
+	return c.SearchSince(ctx, query, since)
+}
+
+// Digest returns the papers in any of cats submitted strictly after since, newest first
+// and without duplicates, at most limit of them (all if limit <= 0): the daily digest of
+// a set of categories, e.g. Digest(ctx, []Category{CategoryCSLG, CategoryStatML}, yesterday, 50).
+// Paging stops at the first paper published at or before since, as in SearchSince, or
+// once limit papers are collected. Cross-listed papers appear once.
+func (c *Client) Digest(ctx context.Context, cats []Category, since time.Time, limit int) ([]*Paper, error) {
+	var terms []string
+	for _, cat := range cats {
+		if cat != "" {
+			terms = append(terms, "cat:"+string(cat))
+		}
+	}
+	if len(terms) == 0 {
+		return nil, NewAPIError(ErrorTypeInvalidQuery, "categories cannot be empty", nil)
+	}
+
+	// As in ListRecent, the UTC day keeps arXiv's day-based date match from skipping papers
+	from := since.UTC()
+	query := &Query{
+		SearchQuery:       strings.Join(terms, " OR "),
+		SubmittedDateFrom: &from,
+		SortBy:            string(SortBySubmittedDate),
+		SortOrder:         string(SortOrderDescending),
+	}
+
+	var papers []*Paper
+	seen := make(map[string]bool)
+	it := c.Iterator(ctx, query)
+	for paper := range it.All() {
+		if !paper.PublishedAt.After(since) {
+			break
+		}
+		// Papers submitted meanwhile shift the pages, so a paper may be served twice
+		if seen[paper.Key()] {
+			continue
+		}
+		seen[paper.Key()] = true
+		papers = append(papers, paper)
+		if limit > 0 && len(papers) >= limit {
+			break
+		}
+	}
+	if err := it.Error(); err != nil {
+		return nil, err
+	}
+	return papers, nil
+}
+
 // NewQuery creates a new QueryBuilder instance
 func (c *Client) NewQuery() *QueryBuilder {
-	return &QueryBuilder{
-		client:     c,
-		maxResults: defaultMaxResults,
-		limit:      defaultLimit,
-		sortBy:     SortByRelevance,
-		sortOrder:  SortOrderDescending,
-	}
+	qb := NewQueryBuilder(c)
+	qb.maxResults = c.effectiveMaxResults()
+	return qb
 }
 
 // Iterator returns an iterator for paginated results
@@ -269,7 +972,7 @@ func (c *Client) buildQueryParams(query *Query) url.Values {
 	// Max results
 	maxResults := query.MaxResults
 	if maxResults <= 0 {
-		maxResults = defaultMaxResults
+		maxResults = c.effectiveMaxResults()
 	}
 	params.Set("max_results", strconv.Itoa(maxResults))
 
@@ -289,6 +992,22 @@ func (c *Client) buildQueryParams(query *Query) url.Values {
 	return params
 }
 
+// effectiveMaxResults returns the max_results fallback for queries that don't set one
+func (c *Client) effectiveMaxResults() int {
+	if c.options.DefaultMaxResults <= 0 {
+		return defaultMaxResults
+	}
+	return c.options.DefaultMaxResults
+}
+
+// idBatchSize returns the number of IDs to send per id_list request
+func (c *Client) idBatchSize() int {
+	if c.options.IDBatchSize <= 0 {
+		return defaultIDBatchSize
+	}
+	return c.options.IDBatchSize
+}
+
 // buildDateRangeFilter builds a date range filter for the search query
 func (c *Client) buildDateRangeFilter(from, to *time.Time) string {
 	const dateFormat = "20060102"
@@ -308,16 +1027,39 @@ func (c *Client) buildDateRangeFilter(from, to *time.Time) string {
 // retryWithBackoff executes a function with exponential backoff retry logic
 func (c *Client) retryWithBackoff(ctx context.Context, fn func() error) error {
 	var lastErr error
+	start := time.Now()
 	for attempt := 0; attempt < c.options.RetryAttempts; attempt++ {
+		if err := c.breaker.allow(); err != nil {
+			c.stats.recordError(err)
+			return err
+		}
+
 		// Execute the function
 		err := fn()
 		if err == nil {
+			c.breaker.record(nil)
 			return nil
 		}
 
+		// The caller gave up; report that rather than whatever the request failed with
+		if ctx.Err() != nil {
+			c.breaker.release()
+			err = newContextError(ctx)
+			c.stats.recordError(err)
+			return err
+		}
+
+		c.breaker.record(err)
+		c.stats.recordError(err)
 		lastErr = err
 		var apiErr *APIError
-		if !errors.As(err, &apiErr) || !apiErr.Retry {
+		if !errors.As(err, &apiErr) {
+			return err
+		}
+		if attempt > 0 {
+			apiErr.Attempts = attempt + 1
+		}
+		if !apiErr.Retry && !(c.options.RetryNotFound && apiErr.Type == ErrorTypeNotFound) {
 			return err
 		}
 
@@ -327,41 +1069,68 @@ func (c *Client) retryWithBackoff(ctx context.Context, fn func() error) error {
 			if attempt != 0 {
 				delay = c.options.RetryDelay
 			}
+			// Retrying would exceed the retry budget
+			if budget := c.options.MaxElapsedRetryTime; budget > 0 && time.Since(start)+delay > budget {
+				return err
+			}
 			// Wait before retrying
 			select {
 			case <-ctx.Done():
-				return ctx.Err()
+				return newContextError(ctx)
 			case <-time.After(delay):
 			}
+			c.stats.retries.Add(1)
 		}
 	}
 	return lastErr
 }
 
-// applyRateLimit ensures we don't exceed the configured rate limit and updates lastRequest
+// newContextError wraps the error of a done context in a non-retryable ErrorTypeTimeout
+// APIError, so errors.Is(err, context.Canceled) and errors.Is(err, context.DeadlineExceeded)
+// keep working through Unwrap
+func newContextError(ctx context.Context) *APIError {
+	apiErr := NewAPIError(ErrorTypeTimeout, "context done", ctx.Err())
+	apiErr.Retry = false
+	return apiErr
+}
+
+// applyRateLimit waits until RateLimit has passed since the previous request, then records
+// the current time as the last request. The mutex is held while waiting, so concurrent
+// callers queue up and go out one RateLimit apart.
 func (c *Client) applyRateLimit(ctx context.Context) error {
 	c.rlMu.Lock()
 	defer c.rlMu.Unlock()
 
-	c.lastRequest = time.Now()
+	if c.options.RateLimit > 0 && !c.lastRequest.IsZero() {
+		if wait := c.options.RateLimit - time.Since(c.lastRequest); wait > 0 {
+			t := time.NewTimer(wait)
+			defer t.Stop()
 
-	if c.options.RateLimit <= 0 {
-		return nil
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-t.C:
+			}
+		}
 	}
 
-	elapsed := time.Since(c.lastRequest)
-	if elapsed >= c.options.RateLimit {
-		return nil
-	}
+	c.lastRequest = time.Now()
+	return nil
+}
 
-	wait := c.options.RateLimit - elapsed
-	t := time.NewTimer(wait)
-	defer t.Stop()
+// getBuffer returns an empty buffer from the client's pool
+func (c *Client) getBuffer() *bytes.Buffer {
+	if buf, ok := c.bufPool.Get().(*bytes.Buffer); ok {
+		buf.Reset()
+		return buf
+	}
+	return new(bytes.Buffer)
+}
 
-	select {
-	case <-ctx.Done():
-		return ctx.Err()
-	case <-t.C:
-		return nil
+// putBuffer returns a buffer to the pool unless it has grown too large to keep around
+func (c *Client) putBuffer(buf *bytes.Buffer) {
+	if buf.Cap() > maxPooledBufferSize {
+		return
 	}
+	c.bufPool.Put(buf)
 }
@@ -1,10 +1,20 @@
 package arxiv
 
 import (
+	"bytes"
 	"context"
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -68,6 +78,21 @@ func TestNewClient(t *testing.T) {
 	}
 }
 
+func TestNewPoliteClient(t *testing.T) {
+	opts := NewPoliteClient().options
+	if opts.RateLimit != 3*time.Second {
+		t.Errorf("Expected polite RateLimit 3s, got %v", opts.RateLimit)
+	}
+	if opts.RetryAttempts != politeRetryAttempts || opts.RetryDelay != politeRetryDelay {
+		t.Errorf("Expected polite retries %d/%v, got %d/%v", politeRetryAttempts, politeRetryDelay, opts.RetryAttempts, opts.RetryDelay)
+	}
+
+	// Everything else keeps the defaults
+	if opts.UserAgent != defaultUserAgent || opts.Timeout != defaultTimeout || opts.PreserveWhitespace {
+		t.Errorf("Expected remaining options to keep their defaults, got %+v", opts)
+	}
+}
+
 func TestNewClientWithHTTPClient(t *testing.T) {
 	customClient := &http.Client{Timeout: 10 * time.Second}
 	client := NewClientWithHTTPClient(customClient)
@@ -85,6 +110,87 @@ func TestNewClientWithHTTPClient(t *testing.T) {
 	}
 }
 
+// roundTripFunc adapts a function to http.RoundTripper
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestNewClientWithOptionsAndHTTPClient(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Instrumented") != "yes" {
+			t.Error("Expected the request to pass through the custom transport")
+		}
+		if ua := r.Header.Get("User-Agent"); ua != "harvester/2.0" {
+			t.Errorf("Expected the options' User-Agent, got %q", ua)
+		}
+		w.Write([]byte(mockXMLResponse))
+	}))
+	defer server.Close()
+
+	var roundTrips atomic.Int32
+	custom := &http.Client{
+		Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			roundTrips.Add(1)
+			req = req.Clone(req.Context())
+			req.Header.Set("X-Instrumented", "yes")
+			return http.DefaultTransport.RoundTrip(req)
+		}),
+	}
+
+	const rateLimit = 100 * time.Millisecond
+	client := NewClientWithOptionsAndHTTPClient(ClientOptions{
+		RateLimit: rateLimit,
+		UserAgent: "harvester/2.0",
+		Timeout:   5 * time.Second,
+	}, custom)
+	client.baseURL = server.URL
+
+	start := time.Now()
+	for range 2 {
+		if _, err := client.Search(context.Background(), &Query{SearchQuery: "test"}); err != nil {
+			t.Fatalf("Search failed: %v", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < rateLimit {
+		t.Errorf("Expected the options' rate limit to space requests, took %v", elapsed)
+	}
+	if n := roundTrips.Load(); n != 2 {
+		t.Errorf("Expected 2 round trips through the custom transport, got %d", n)
+	}
+
+	// The options' timeout fills in, without modifying the caller's client
+	if client.httpClient.Timeout != 5*time.Second {
+		t.Errorf("Expected the options' Timeout, got %v", client.httpClient.Timeout)
+	}
+	if custom.Timeout != 0 || client.httpClient == custom {
+		t.Error("Expected the caller's HTTP client to be left unchanged")
+	}
+
+	// The HTTP client's own timeout wins when set
+	withTimeout := NewClientWithOptionsAndHTTPClient(ClientOptions{Timeout: 5 * time.Second}, &http.Client{Timeout: time.Second})
+	if withTimeout.httpClient.Timeout != time.Second {
+		t.Errorf("Expected the HTTP client's Timeout to win, got %v", withTimeout.httpClient.Timeout)
+	}
+
+	// The proxy option applies to a standard transport
+	proxyURL, _ := url.Parse("http://proxy.example:8080")
+	proxied := NewClientWithOptionsAndHTTPClient(ClientOptions{Proxy: proxyURL}, &http.Client{})
+	transport, ok := proxied.httpClient.Transport.(*http.Transport)
+	if !ok {
+		t.Fatalf("Expected an *http.Transport, got %T", proxied.httpClient.Transport)
+	}
+	req, _ := http.NewRequest("GET", "https://export.arxiv.org/api/query", nil)
+	if got, err := transport.Proxy(req); err != nil || got.String() != proxyURL.String() {
+		t.Errorf("Expected proxy %s, got %v (%v)", proxyURL, got, err)
+	}
+
+	if c := NewClientWithOptionsAndHTTPClient(ClientOptions{}, nil); c.httpClient == nil {
+		t.Error("Expected a nil HTTP client to fall back to the default one")
+	}
+}
+
 func TestNewClientWithOptions(t *testing.T) {
 	opts := ClientOptions{
 		RetryAttempts: 5,
@@ -164,6 +270,46 @@ func TestNewClientWithOptionsZeroValues(t *testing.T) {
 	}
 }
 
+func TestNewClientWithOptionsDefaultMaxResults(t *testing.T) {
+	client := NewClientWithOptions(ClientOptions{DefaultMaxResults: 100})
+
+	params := client.buildQueryParams(&Query{SearchQuery: "test"})
+	if got := params.Get("max_results"); got != "100" {
+		t.Errorf("Expected max_results 100 from DefaultMaxResults, got %s", got)
+	}
+
+	params = client.buildQueryParams(&Query{SearchQuery: "test", MaxResults: 7})
+	if got := params.Get("max_results"); got != "7" {
+		t.Errorf("Expected explicit max_results 7 to win, got %s", got)
+	}
+
+	if qb := client.NewQuery(); qb.maxResults != 100 {
+		t.Errorf("Expected NewQuery maxResults 100, got %d", qb.maxResults)
+	}
+
+	client = NewClientWithOptions(ClientOptions{DefaultMaxResults: 50000})
+	if client.options.DefaultMaxResults != maxResultsLimit {
+		t.Errorf("Expected DefaultMaxResults clamped to %d, got %d", maxResultsLimit, client.options.DefaultMaxResults)
+	}
+
+	client = NewClientWithOptions(ClientOptions{DefaultMaxResults: -1})
+	if client.options.DefaultMaxResults != defaultMaxResults {
+		t.Errorf("Expected negative DefaultMaxResults replaced with %d, got %d", defaultMaxResults, client.options.DefaultMaxResults)
+	}
+
+	// Validate reports the values that would be replaced or clamped
+	for _, value := range []int{0, 1, maxResultsLimit} {
+		if err := (ClientOptions{DefaultMaxResults: value}).Validate(); err != nil {
+			t.Errorf("Expected DefaultMaxResults %d to be valid, got %v", value, err)
+		}
+	}
+	for _, value := range []int{-1, maxResultsLimit + 1} {
+		if err := (ClientOptions{DefaultMaxResults: value}).Validate(); !IsInvalidQuery(err) {
+			t.Errorf("Expected DefaultMaxResults %d to be rejected, got %v", value, err)
+		}
+	}
+}
+
 // =============================================================================
 // HTTP Communication Tests
 // =============================================================================
@@ -233,6 +379,158 @@ func TestSearch(t *testing.T) {
 	}
 }
 
+// newDatedServer serves total papers, paper i published on day i+1 of January 2023,
+// honoring the start and max_results parameters
+func newDatedServer(total int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start, _ := strconv.Atoi(r.URL.Query().Get("start"))
+		maxResults, _ := strconv.Atoi(r.URL.Query().Get("max_results"))
+		end := min(start+maxResults, total)
+
+		var b strings.Builder
+		fmt.Fprintf(&b, `<?xml version="1.0" encoding="UTF-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom" xmlns:opensearch="http://a9.com/-/spec/opensearch/1.1/">
+  <opensearch:totalResults>%d</opensearch:totalResults>
+  <opensearch:startIndex>%d</opensearch:startIndex>
+  <opensearch:itemsPerPage>%d</opensearch:itemsPerPage>`, total, start, maxResults)
+		for i := start; i < end; i++ {
+			fmt.Fprintf(&b, `
+  <entry>
+    <id>http://arxiv.org/abs/2301.%05dv1</id>
+    <title>Paper %d</title>
+    <published>2023-01-%02dT12:00:00Z</published>
+    <updated>2023-01-%02dT12:00:00Z</updated>
+  </entry>`, i, i, i+1, i+1)
+		}
+		b.WriteString("\n</feed>")
+
+		w.Header().Set("Content-Type", "application/xml")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(b.String()))
+	}))
+}
+
+func TestSearchStrictDateFilter(t *testing.T) {
+	server := newDatedServer(3)
+	defer server.Close()
+
+	client := newFastClient(server.URL)
+	from := time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2023, 1, 2, 23, 59, 59, 0, time.UTC)
+	query := &Query{SearchQuery: "test", MaxResults: 3, SubmittedDateFrom: &from, SubmittedDateTo: &to}
+
+	// The mock ignores the date range, like arXiv at the boundaries
+	results, err := client.Search(context.Background(), query)
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if len(results.Papers) != 3 {
+		t.Fatalf("Expected 3 papers without strict filtering, got %d", len(results.Papers))
+	}
+
+	query.StrictDateFilter = true
+	results, err = client.Search(context.Background(), query)
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if len(results.Papers) != 1 || results.Papers[0].ID != "2301.00001v1" {
+		t.Fatalf("Expected only the paper published on 2023-01-02, got %+v", results.Papers)
+	}
+	if results.Papers[0].RelevanceRank() != 1 {
+		t.Errorf("Expected relevance rank from the unfiltered page (1), got %d", results.Papers[0].RelevanceRank())
+	}
+}
+
+func TestIteratorStrictDateFilter(t *testing.T) {
+	server := newDatedServer(10)
+	defer server.Close()
+
+	client := newFastClient(server.URL)
+	from := time.Date(2023, 1, 6, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2023, 1, 8, 0, 0, 0, 0, time.UTC)
+	query := &Query{SearchQuery: "test", MaxResults: 2, SubmittedDateFrom: &from, SubmittedDateTo: &to, StrictDateFilter: true}
+
+	// Pages before the range are emptied entirely by the filter and must not end iteration
+	papers, err := NewIterator(client, query, context.Background()).Collect()
+	if err != nil {
+		t.Fatalf("Collect error: %v", err)
+	}
+	var ids []string
+	for _, paper := range papers {
+		ids = append(ids, paper.ID)
+	}
+	if !slices.Equal(ids, []string{"2301.00005v1", "2301.00006v1"}) {
+		t.Errorf("Expected the papers of 2023-01-06 and 2023-01-07, got %v", ids)
+	}
+}
+
+func TestSearchRelevanceRank(t *testing.T) {
+	server := newPagingServer(7)
+	defer server.Close()
+
+	client := newFastClient(server.URL)
+
+	papers, err := client.NewQuery().SearchQuery("test").MaxResults(3).Iterator(context.Background()).Collect()
+	if err != nil {
+		t.Fatalf("Collect error: %v", err)
+	}
+	for i, paper := range papers {
+		if paper.RelevanceRank() != i {
+			t.Errorf("Expected paper %d to have relevance rank %d, got %d", i, i, paper.RelevanceRank())
+		}
+	}
+
+	results, err := client.Search(context.Background(), &Query{SearchQuery: "test", MaxResults: 3, SortBy: string(SortBySubmittedDate), SortOrder: string(SortOrderDescending)})
+	if err != nil {
+		t.Fatalf("Search error: %v", err)
+	}
+	if results.SortBy != string(SortBySubmittedDate) || results.SortOrder != string(SortOrderDescending) {
+		t.Errorf("Expected results to record the query sort, got '%s' '%s'", results.SortBy, results.SortOrder)
+	}
+	if rank := results.Papers[0].RelevanceRank(); rank != -1 {
+		t.Errorf("Expected rank -1 when not sorted by relevance, got %d", rank)
+	}
+
+	if rank := (&Paper{}).RelevanceRank(); rank != -1 {
+		t.Errorf("Expected rank -1 for a constructed paper, got %d", rank)
+	}
+}
+
+func TestDefaultClient(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "application/atom+xml")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(mockXMLResponse))
+	}))
+	defer server.Close()
+
+	lazy := getDefaultClient()
+	if lazy == nil || lazy != getDefaultClient() || lazy.baseURL != baseURL {
+		t.Fatalf("Expected a single lazily created default client, got %+v", lazy)
+	}
+
+	SetDefaultClient(newFastClient(server.URL))
+	defer SetDefaultClient(lazy)
+
+	if _, err := Search(context.Background(), &Query{SearchQuery: "test", MaxResults: 1}); err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	paper, err := GetByID(context.Background(), "1234.5678")
+	if err != nil {
+		t.Fatalf("GetByID failed: %v", err)
+	}
+	if paper.ID != "1234.5678v1" || requests != 2 {
+		t.Errorf("Expected both calls to use the swapped client, got %d requests", requests)
+	}
+
+	SetDefaultClient(nil)
+	if client := getDefaultClient(); client == nil || client.baseURL != baseURL {
+		t.Errorf("Expected SetDefaultClient(nil) to restore a default client, got %+v", client)
+	}
+}
+
 func TestGetByID(t *testing.T) {
 	// Create a test server
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -267,149 +565,875 @@ func TestGetByID(t *testing.T) {
 	}
 }
 
-func TestSearchWithCustomUserAgent(t *testing.T) {
-	// Create a test server that checks User-Agent
+func TestGetLatestVersion(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if ua := r.Header.Get("User-Agent"); ua != "custom-agent/2.0" {
-			t.Errorf("Expected User-Agent 'custom-agent/2.0', got '%s'", ua)
+		if idList := r.URL.Query().Get("id_list"); idList != "1234.5678" {
+			t.Errorf("Expected version-less id_list '1234.5678', got '%s'", idList)
 		}
 
 		w.Header().Set("Content-Type", "application/atom+xml")
 		w.WriteHeader(http.StatusOK)
-		w.Write([]byte(mockXMLResponse))
+		w.Write([]byte(strings.ReplaceAll(mockXMLResponse, "1234.5678v1", "1234.5678v3")))
 	}))
 	defer server.Close()
 
-	// Create client with custom User-Agent
-	client := NewClientWithOptions(ClientOptions{
-		UserAgent: "custom-agent/2.0",
-	})
+	client := NewClient()
 	client.baseURL = server.URL
 
-	query := &Query{
-		SearchQuery: "test",
-		MaxResults:  1,
+	paper, err := client.GetLatestVersion(context.Background(), "1234.5678v2")
+	if err != nil {
+		t.Fatalf("GetLatestVersion failed: %v", err)
 	}
 
-	_, err := client.Search(context.Background(), query)
-	if err != nil {
-		t.Fatalf("Search failed: %v", err)
+	if paper.ID != "1234.5678v3" {
+		t.Errorf("Expected latest version '1234.5678v3', got '%s'", paper.ID)
 	}
 }
 
-// =============================================================================
-// Error Handling Tests
-// =============================================================================
+func TestGetVersionsMap(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if idList := r.URL.Query().Get("id_list"); idList != "1234.5678v1,1234.5678v2,1234.5678v3" {
+			t.Errorf("Expected versioned id_list, got '%s'", idList)
+		}
 
-func TestSearchWithNilQuery(t *testing.T) {
-	client := NewClient()
-	_, err := client.Search(context.Background(), nil)
-	if err == nil {
-		t.Error("Expected error for nil query, got nil")
+		// Version 3 doesn't exist
+		var b strings.Builder
+		b.WriteString(`<?xml version="1.0" encoding="UTF-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom">
+  <opensearch:totalResults xmlns:opensearch="http://a9.com/-/spec/opensearch/1.1/">2</opensearch:totalResults>`)
+		for version := 1; version <= 2; version++ {
+			fmt.Fprintf(&b, `
+  <entry>
+    <id>http://arxiv.org/abs/1234.5678v%d</id>
+    <title>Revision %d</title>
+    <published>2023-01-01T00:00:00Z</published>
+    <updated>2023-0%d-01T00:00:00Z</updated>
+  </entry>`, version, version, version)
+		}
+		b.WriteString("\n</feed>")
+		w.Write([]byte(b.String()))
+	}))
+	defer server.Close()
+
+	client := newFastClient(server.URL)
+	papers, err := client.GetVersionsMap(context.Background(), "1234.5678v2", 1, 2, 3, 1)
+	if err != nil {
+		t.Fatalf("GetVersionsMap failed: %v", err)
 	}
 
-	apiErr, ok := err.(*APIError)
-	if !ok {
-		t.Error("Expected APIError type")
+	if len(papers) != 3 {
+		t.Errorf("Expected 3 versions, got %d", len(papers))
+	}
+	for version := 1; version <= 2; version++ {
+		if paper := papers[version]; paper == nil || paper.Title != fmt.Sprintf("Revision %d", version) {
+			t.Errorf("Expected revision %d, got %+v", version, paper)
+		}
+	}
+	if paper, ok := papers[3]; !ok || paper != nil {
+		t.Errorf("Expected a nil entry for the missing version 3, got %+v, %v", paper, ok)
 	}
 
-	if apiErr.Type != ErrorTypeInvalidQuery {
-		t.Errorf("Expected ErrorTypeInvalidQuery, got %v", apiErr.Type)
+	if _, err := client.GetVersionsMap(context.Background(), "1234.5678"); !IsInvalidQuery(err) {
+		t.Errorf("Expected invalid query error for no versions, got %v", err)
+	}
+	if _, err := client.GetVersionsMap(context.Background(), "1234.5678", 0); !IsInvalidQuery(err) {
+		t.Errorf("Expected invalid query error for version 0, got %v", err)
 	}
 }
 
-func TestGetByIDWithEmptyID(t *testing.T) {
-	client := NewClient()
-	_, err := client.GetByID(context.Background(), "")
-	if err == nil {
-		t.Error("Expected error for empty ID, got nil")
+func TestGetByIDs(t *testing.T) {
+	var requests [][]string
+	server := newIDListServer(&requests)
+	defer server.Close()
+
+	client := NewClientWithOptions(ClientOptions{RateLimit: time.Nanosecond, IDBatchSize: 2})
+	client.baseURL = server.URL
+
+	ids := testIDs(5)
+	papers, err := client.GetByIDs(context.Background(), ids)
+	if err != nil {
+		t.Fatalf("GetByIDs failed: %v", err)
 	}
 
-	apiErr, ok := err.(*APIError)
-	if !ok {
-		t.Error("Expected APIError type")
+	if len(requests) != 3 {
+		t.Errorf("Expected 3 requests with batch size 2, got %d", len(requests))
+	}
+	if len(papers) != 5 {
+		t.Fatalf("Expected 5 papers, got %d", len(papers))
+	}
+	for i, paper := range papers {
+		if paper.ID != ids[i]+"v1" {
+			t.Errorf("Expected paper %d to be %sv1, got %s", i, ids[i], paper.ID)
+		}
 	}
 
-	if apiErr.Type != ErrorTypeInvalidQuery {
-		t.Errorf("Expected ErrorTypeInvalidQuery, got %v", apiErr.Type)
+	if _, err := client.GetByIDs(context.Background(), nil); !IsInvalidQuery(err) {
+		t.Errorf("Expected invalid query error for empty ids, got %v", err)
 	}
 }
 
-func TestGetByIDNotFound(t *testing.T) {
+func TestGetByIDsMatching(t *testing.T) {
+	var requests []string
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// Return empty feed
-		emptyResponse := `<?xml version="1.0" encoding="UTF-8"?>
+		requests = append(requests, r.URL.RawQuery)
+		ids := strings.Split(r.URL.Query().Get("id_list"), ",")
+
+		// Even IDs are in cs.LG, odd ones in math.CO
+		var b strings.Builder
+		fmt.Fprintf(&b, `<?xml version="1.0" encoding="UTF-8"?>
 <feed xmlns="http://www.w3.org/2005/Atom">
-  <opensearch:totalResults xmlns:opensearch="http://a9.com/-/spec/opensearch/1.1/">0</opensearch:totalResults>
-  <opensearch:startIndex xmlns:opensearch="http://a9.com/-/spec/opensearch/1.1/">0</opensearch:startIndex>
-  <opensearch:itemsPerPage xmlns:opensearch="http://a9.com/-/spec/opensearch/1.1/">0</opensearch:itemsPerPage>
-</feed>`
-		w.Header().Set("Content-Type", "application/atom+xml")
-		w.WriteHeader(http.StatusOK)
-		w.Write([]byte(emptyResponse))
+  <opensearch:totalResults xmlns:opensearch="http://a9.com/-/spec/opensearch/1.1/">%d</opensearch:totalResults>`, len(ids))
+		for _, id := range ids {
+			n, _ := strconv.Atoi(strings.TrimPrefix(id, "2301."))
+			category := "cs.LG"
+			if n%2 == 1 {
+				category = "math.CO"
+			}
+			fmt.Fprintf(&b, `
+  <entry>
+    <id>http://arxiv.org/abs/%sv1</id>
+    <title>Paper %s</title>
+    <published>2023-01-01T00:00:00Z</published>
+    <updated>2023-01-01T00:00:00Z</updated>
+    <category term="%s" scheme="http://arxiv.org/schemas/atom"/>
+  </entry>`, id, id, category)
+		}
+		b.WriteString("\n</feed>")
+		w.Write([]byte(b.String()))
 	}))
 	defer server.Close()
 
-	client := NewClient()
+	client := NewClientWithOptions(ClientOptions{RateLimit: time.Nanosecond, IDBatchSize: 3})
 	client.baseURL = server.URL
 
-	_, err := client.GetByID(context.Background(), "nonexistent")
-	if err == nil {
-		t.Fatal("Expected error for nonexistent paper")
+	papers, err := client.GetByIDsMatching(context.Background(), testIDs(7), HasCategory(CategoryCSLG))
+	if err != nil {
+		t.Fatalf("GetByIDsMatching failed: %v", err)
 	}
 
-	apiErr, ok := err.(*APIError)
-	if !ok {
-		t.Fatalf("Expected APIError, got %T", err)
+	var ids []string
+	for _, paper := range papers {
+		ids = append(ids, paper.ID)
+	}
+	expected := []string{"2301.00000v1", "2301.00002v1", "2301.00004v1", "2301.00006v1"}
+	if !slices.Equal(ids, expected) {
+		t.Errorf("Expected %v, got %v", expected, ids)
+	}
+	if len(requests) != 3 {
+		t.Errorf("Expected 3 id_list batches, got %d", len(requests))
+	}
+	for _, request := range requests {
+		if strings.Contains(request, "search_query") {
+			t.Errorf("Expected no search_query alongside id_list, got %s", request)
+		}
 	}
 
-	if apiErr.Type != ErrorTypeNotFound {
-		t.Errorf("Expected ErrorTypeNotFound, got %v", apiErr.Type)
+	all, err := client.GetByIDsMatching(context.Background(), testIDs(2), nil)
+	if err != nil || len(all) != 2 {
+		t.Errorf("Expected a nil predicate to keep both papers, got %d, %v", len(all), err)
+	}
+	if _, err := client.GetByIDsMatching(context.Background(), nil, nil); !IsInvalidQuery(err) {
+		t.Errorf("Expected invalid query error for empty ids, got %v", err)
 	}
 }
 
-func TestSearchNetworkError(t *testing.T) {
-	// Create client with invalid URL to trigger network error
-	client := NewClient()
-	client.baseURL = "http://invalid-url-that-does-not-exist"
+func TestGetByIDsConcurrent(t *testing.T) {
+	ids := testIDs(23)
+	var requests [][]string
+	server := newIDListServer(&requests, ids[4], ids[17])
+	defer server.Close()
 
-	query := &Query{
-		SearchQuery: "test",
-		MaxResults:  1,
+	client := NewClientWithOptions(ClientOptions{RateLimit: time.Nanosecond, IDBatchSize: 5})
+	client.baseURL = server.URL
+
+	papers, err := client.GetByIDsConcurrent(context.Background(), ids, 3)
+	if err != nil {
+		t.Fatalf("GetByIDsConcurrent failed: %v", err)
 	}
 
-	_, err := client.Search(context.Background(), query)
-	if err == nil {
-		t.Error("Expected network error, got nil")
+	if len(requests) != 5 {
+		t.Errorf("Expected 5 requests with batch size 5, got %d", len(requests))
+	}
+	if len(papers) != len(ids) {
+		t.Fatalf("Expected %d entries, got %d", len(ids), len(papers))
+	}
+	for i, paper := range papers {
+		switch {
+		case i == 4 || i == 17:
+			if paper != nil {
+				t.Errorf("Expected nil entry %d for a missing ID, got %s", i, paper.ID)
+			}
+		case paper == nil:
+			t.Errorf("Expected paper %d, got nil", i)
+		case paper.ID != ids[i]+"v1":
+			t.Errorf("Expected paper %d to be %sv1, got %s", i, ids[i], paper.ID)
+		}
 	}
 
-	apiErr, ok := err.(*APIError)
-	if !ok {
-		t.Error("Expected APIError type")
+	if _, err := client.GetByIDsConcurrent(context.Background(), nil, 3); !IsInvalidQuery(err) {
+		t.Errorf("Expected invalid query error for empty ids, got %v", err)
 	}
+}
 
-	if apiErr.Type != ErrorTypeNetwork {
-		t.Errorf("Expected ErrorTypeNetwork, got %v", apiErr.Type)
+func TestNewClientWithOptionsIDBatchSize(t *testing.T) {
+	if got := NewClient().options.IDBatchSize; got != defaultIDBatchSize {
+		t.Errorf("Expected default IDBatchSize %d, got %d", defaultIDBatchSize, got)
+	}
+	if got := NewClientWithOptions(ClientOptions{IDBatchSize: -5}).options.IDBatchSize; got != defaultIDBatchSize {
+		t.Errorf("Expected negative IDBatchSize replaced with %d, got %d", defaultIDBatchSize, got)
+	}
+	if got := NewClientWithOptions(ClientOptions{IDBatchSize: 5000}).options.IDBatchSize; got != maxIDBatchSize {
+		t.Errorf("Expected IDBatchSize clamped to %d, got %d", maxIDBatchSize, got)
 	}
 }
 
-// =============================================================================
-// Retry Mechanism Tests
-// =============================================================================
+func TestNewClientWithOptionsProxy(t *testing.T) {
+	if transport := NewClient().httpClient.Transport; transport != nil {
+		t.Errorf("Expected default transport without a proxy, got %T", transport)
+	}
 
-func TestSearchWithRetryRateLimit(t *testing.T) {
-	attempts := 0
-	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		attempts++
-		if attempts <= 2 {
-			w.WriteHeader(http.StatusTooManyRequests)
-			return
-		}
-		w.Header().Set("Content-Type", "application/atom+xml")
-		w.WriteHeader(http.StatusOK)
-		w.Write([]byte(mockXMLResponse))
-	}))
-	defer server.Close()
+	proxyURL, _ := url.Parse("http://proxy.example.com:8080")
+	client := NewClientWithOptions(ClientOptions{Proxy: proxyURL})
+	transport, ok := client.httpClient.Transport.(*http.Transport)
+	if !ok {
+		t.Fatalf("Expected *http.Transport, got %T", client.httpClient.Transport)
+	}
+
+	req, _ := http.NewRequest("GET", baseURL, nil)
+	got, err := transport.Proxy(req)
+	if err != nil {
+		t.Fatalf("Proxy returned error: %v", err)
+	}
+	if got.String() != proxyURL.String() {
+		t.Errorf("Expected proxy %s, got %s", proxyURL, got)
+	}
+
+	for _, invalid := range []string{"ftp://proxy.example.com", "http://"} {
+		proxyURL, _ := url.Parse(invalid)
+		transport := NewClientWithOptions(ClientOptions{Proxy: proxyURL}).httpClient.Transport.(*http.Transport)
+		if _, err := transport.Proxy(req); err == nil {
+			t.Errorf("Expected error for invalid proxy %q", invalid)
+		}
+	}
+}
+
+func TestSearchThroughProxy(t *testing.T) {
+	// An HTTP proxy receives the request with the absolute target URL
+	var target string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		target = r.URL.String()
+		w.Header().Set("Content-Type", "application/atom+xml")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(mockXMLResponse))
+	}))
+	defer proxy.Close()
+
+	proxyURL, _ := url.Parse(proxy.URL)
+	client := NewClientWithOptions(ClientOptions{RateLimit: time.Nanosecond, Proxy: proxyURL})
+	client.baseURL = "http://export.arxiv.org/api/query"
+
+	if _, err := client.Search(context.Background(), &Query{SearchQuery: "test", MaxResults: 1}); err != nil {
+		t.Fatalf("Search through proxy failed: %v", err)
+	}
+	if !strings.HasPrefix(target, "http://export.arxiv.org/api/query?") {
+		t.Errorf("Expected proxied request for the arXiv URL, got %s", target)
+	}
+}
+
+func TestSearchWithCustomUserAgent(t *testing.T) {
+	// Create a test server that checks User-Agent
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if ua := r.Header.Get("User-Agent"); ua != "custom-agent/2.0" {
+			t.Errorf("Expected User-Agent 'custom-agent/2.0', got '%s'", ua)
+		}
+
+		w.Header().Set("Content-Type", "application/atom+xml")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(mockXMLResponse))
+	}))
+	defer server.Close()
+
+	// Create client with custom User-Agent
+	client := NewClientWithOptions(ClientOptions{
+		UserAgent: "custom-agent/2.0",
+	})
+	client.baseURL = server.URL
+
+	query := &Query{
+		SearchQuery: "test",
+		MaxResults:  1,
+	}
+
+	_, err := client.Search(context.Background(), query)
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+}
+
+func TestSearchWithRequestID(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if id := r.Header.Get("X-Request-ID"); id != "req-123" {
+			t.Errorf("Expected X-Request-ID 'req-123', got '%s'", id)
+		}
+
+		w.Header().Set("Content-Type", "application/atom+xml")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(mockXMLResponse))
+	}))
+	defer server.Close()
+
+	var debug bytes.Buffer
+	client := NewClientWithOptions(ClientOptions{RateLimit: time.Nanosecond, Debug: true, DebugWriter: &debug})
+	client.baseURL = server.URL
+
+	ctx := WithRequestID(context.Background(), "req-123")
+	if id, ok := RequestIDFromContext(ctx); !ok || id != "req-123" {
+		t.Errorf("Expected request ID 'req-123', got '%s' (ok=%v)", id, ok)
+	}
+
+	query := &Query{
+		SearchQuery: "test",
+		MaxResults:  1,
+	}
+
+	_, err := client.Search(ctx, query)
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+
+	// The debug output ties the request to the correlation ID
+	reqURL, _ := client.QueryURL(query)
+	if output := debug.String(); !strings.Contains(output, "GET "+reqURL+" (request ID req-123)\n") {
+		t.Errorf("Expected the request ID in the debug output, got:\n%s", output)
+	}
+}
+
+func TestSearchDebug(t *testing.T) {
+	var fail atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if fail.Load() {
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte("backend exploded"))
+			return
+		}
+		w.Header().Set("Content-Type", "application/atom+xml")
+		w.Write([]byte(mockXMLResponse))
+	}))
+	defer server.Close()
+
+	var debug bytes.Buffer
+	client := NewClientWithOptions(ClientOptions{
+		RetryAttempts: 1,
+		RateLimit:     time.Nanosecond,
+		Debug:         true,
+		DebugWriter:   &debug,
+	})
+	client.baseURL = server.URL
+
+	query := &Query{SearchQuery: "quantum computing", MaxResults: 1}
+	if _, err := client.Search(context.Background(), query); err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+
+	reqURL, _ := client.QueryURL(query)
+	output := debug.String()
+	if !strings.Contains(output, "GET "+reqURL+"\n") {
+		t.Errorf("Expected debug output to contain the request URL %s, got:\n%s", reqURL, output)
+	}
+	if !strings.Contains(output, "200 OK") || !strings.Contains(output, "<opensearch:totalResults") {
+		t.Errorf("Expected debug output to contain the status and raw body, got:\n%s", output)
+	}
+
+	// Error responses are dumped too
+	debug.Reset()
+	fail.Store(true)
+	if _, err := client.Search(context.Background(), query); err == nil {
+		t.Fatal("Expected Search to fail")
+	}
+	if output := debug.String(); !strings.Contains(output, "500 Internal Server Error") || !strings.Contains(output, "backend exploded") {
+		t.Errorf("Expected the error status and body in the debug output, got:\n%s", output)
+	}
+
+	// Without Debug nothing is written
+	debug.Reset()
+	fail.Store(false)
+	quiet := NewClientWithOptions(ClientOptions{RateLimit: time.Nanosecond, DebugWriter: &debug})
+	quiet.baseURL = server.URL
+	quiet.Search(context.Background(), query)
+	if debug.Len() != 0 {
+		t.Errorf("Expected no debug output with Debug unset, got:\n%s", debug.String())
+	}
+}
+
+func TestPing(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   int
+		body     string
+		expected error
+	}{
+		{"healthy", http.StatusOK, mockXMLResponse, nil},
+		{"unavailable", http.StatusServiceUnavailable, "", ErrRateLimited},
+		{"not a feed", http.StatusOK, "<html><body>Sign in to the network</body></html>", ErrParsing},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests++
+				if got := r.URL.Query().Get("max_results"); got != "1" {
+					t.Errorf("Expected a one-result request, got max_results=%s", got)
+				}
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			client := newFastClient(server.URL)
+			err := client.Ping(context.Background())
+			if tt.expected == nil {
+				if err != nil {
+					t.Errorf("Expected Ping to succeed, got %v", err)
+				}
+			} else if !errors.Is(err, tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, err)
+			}
+			if requests != 1 {
+				t.Errorf("Expected a single attempt, got %d requests", requests)
+			}
+		})
+	}
+}
+
+// awaitFlight waits for the first request of a shared search to reach the server, then
+// gives the other callers, which block on the flight without a request, time to join it
+func awaitFlight(requests *atomic.Int32) {
+	for requests.Load() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	time.Sleep(50 * time.Millisecond)
+}
+
+func TestSearchSingleflight(t *testing.T) {
+	var requests atomic.Int32
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		<-release
+		w.Write([]byte(mockXMLResponse))
+	}))
+	defer server.Close()
+
+	client := NewClientWithOptions(ClientOptions{RateLimit: time.Nanosecond, Singleflight: true})
+	client.baseURL = server.URL
+
+	const callers = 3
+	results := make([]*SearchResults, callers)
+	errs := make([]error, callers)
+	var wg sync.WaitGroup
+	for i := range callers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i], errs[i] = client.Search(context.Background(), &Query{SearchQuery: "quantum", MaxResults: 1})
+		}()
+	}
+	awaitFlight(&requests)
+	close(release)
+	wg.Wait()
+
+	if n := requests.Load(); n != 1 {
+		t.Errorf("Expected 1 request for %d identical searches, got %d", callers, n)
+	}
+	for i := range callers {
+		if errs[i] != nil {
+			t.Fatalf("Search %d failed: %v", i, errs[i])
+		}
+		if len(results[i].Papers) != 1 || results[i].Papers[0].ID != "1234.5678v1" {
+			t.Errorf("Expected search %d to get the shared paper, got %+v", i, results[i].Papers)
+		}
+	}
+	if results[0] == results[1] || &results[0].Papers[0] == &results[1].Papers[0] ||
+		&results[0].Papers[0].Authors[0] == &results[1].Papers[0].Authors[0] {
+		t.Error("Expected each caller to get its own copy of the results")
+	}
+
+	// Later searches make a new request
+	if _, err := client.Search(context.Background(), &Query{SearchQuery: "quantum", MaxResults: 1}); err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if n := requests.Load(); n != 2 {
+		t.Errorf("Expected a second request after the flight landed, got %d requests", n)
+	}
+}
+
+func TestSearchSingleflightDateZones(t *testing.T) {
+	var requests atomic.Int32
+	var mu sync.Mutex
+	seen := map[string]bool{}
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		mu.Lock()
+		seen[r.URL.Query().Get("search_query")] = true
+		mu.Unlock()
+		<-release
+		w.Write([]byte(mockXMLResponse))
+	}))
+	defer server.Close()
+
+	client := NewClientWithOptions(ClientOptions{RateLimit: time.Nanosecond, Singleflight: true})
+	client.baseURL = server.URL
+
+	// The same instant falls on different days, so the searches send different requests
+	lateUTC := time.Date(2024, 1, 1, 23, 30, 0, 0, time.UTC)
+	earlyParis := lateUTC.In(time.FixedZone("CET", 60*60))
+	queries := []*Query{
+		{SearchQuery: "quantum", MaxResults: 1, SubmittedDateFrom: &lateUTC},
+		{SearchQuery: "quantum", MaxResults: 1, SubmittedDateFrom: &earlyParis},
+	}
+
+	errs := make([]error, len(queries))
+	var wg sync.WaitGroup
+	for i, query := range queries {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, errs[i] = client.Search(context.Background(), query)
+		}()
+	}
+	// Hold the responses until both searches are in flight, or long enough that a shared
+	// flight would have joined them
+	deadline := time.Now().Add(time.Second)
+	for int(requests.Load()) < len(queries) && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	close(release)
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			t.Fatalf("Search %d failed: %v", i, err)
+		}
+	}
+	if n := requests.Load(); n != 2 {
+		t.Errorf("Expected 2 requests for searches on different days, got %d", n)
+	}
+	if len(seen) != 2 {
+		t.Errorf("Expected 2 distinct search queries, got %v", seen)
+	}
+}
+
+func TestRequestIDFromContextMissing(t *testing.T) {
+	if _, ok := RequestIDFromContext(context.Background()); ok {
+		t.Error("Expected no request ID in empty context")
+	}
+}
+
+// =============================================================================
+// Error Handling Tests
+// =============================================================================
+
+func TestSearchWithNilQuery(t *testing.T) {
+	client := NewClient()
+	_, err := client.Search(context.Background(), nil)
+	if err == nil {
+		t.Error("Expected error for nil query, got nil")
+	}
+
+	apiErr, ok := err.(*APIError)
+	if !ok {
+		t.Error("Expected APIError type")
+	}
+
+	if apiErr.Type != ErrorTypeInvalidQuery {
+		t.Errorf("Expected ErrorTypeInvalidQuery, got %v", apiErr.Type)
+	}
+}
+
+func TestSearchWithInvalidSort(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(mockXMLResponse))
+	}))
+	defer server.Close()
+
+	client := newFastClient(server.URL)
+
+	for _, query := range []*Query{
+		{SearchQuery: "test", SortBy: "submitedDate"},
+		{SearchQuery: "test", SortOrder: "desc"},
+	} {
+		_, err := client.Search(context.Background(), query)
+		if !IsInvalidQuery(err) {
+			t.Errorf("Expected invalid query error for %s/%s, got %v", query.SortBy, query.SortOrder, err)
+		}
+	}
+
+	if requests != 0 {
+		t.Errorf("Expected invalid queries not to be sent, got %d requests", requests)
+	}
+
+	// Valid and empty values are accepted
+	if _, err := client.Search(context.Background(), &Query{SearchQuery: "test", SortBy: "lastUpdatedDate", SortOrder: "ascending"}); err != nil {
+		t.Errorf("Expected valid sort to succeed, got %v", err)
+	}
+}
+
+func TestGetByIDWithEmptyID(t *testing.T) {
+	client := NewClient()
+	_, err := client.GetByID(context.Background(), "")
+	if err == nil {
+		t.Error("Expected error for empty ID, got nil")
+	}
+
+	apiErr, ok := err.(*APIError)
+	if !ok {
+		t.Error("Expected APIError type")
+	}
+
+	if apiErr.Type != ErrorTypeInvalidQuery {
+		t.Errorf("Expected ErrorTypeInvalidQuery, got %v", apiErr.Type)
+	}
+}
+
+func TestGetByIDNotFound(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Return empty feed
+		emptyResponse := `<?xml version="1.0" encoding="UTF-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom">
+  <opensearch:totalResults xmlns:opensearch="http://a9.com/-/spec/opensearch/1.1/">0</opensearch:totalResults>
+  <opensearch:startIndex xmlns:opensearch="http://a9.com/-/spec/opensearch/1.1/">0</opensearch:startIndex>
+  <opensearch:itemsPerPage xmlns:opensearch="http://a9.com/-/spec/opensearch/1.1/">0</opensearch:itemsPerPage>
+</feed>`
+		w.Header().Set("Content-Type", "application/atom+xml")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(emptyResponse))
+	}))
+	defer server.Close()
+
+	client := NewClient()
+	client.baseURL = server.URL
+
+	_, err := client.GetByID(context.Background(), "nonexistent")
+	if err == nil {
+		t.Fatal("Expected error for nonexistent paper")
+	}
+
+	apiErr, ok := err.(*APIError)
+	if !ok {
+		t.Fatalf("Expected APIError, got %T", err)
+	}
+
+	if apiErr.Type != ErrorTypeNotFound {
+		t.Errorf("Expected ErrorTypeNotFound, got %v", apiErr.Type)
+	}
+}
+
+func TestGetByIDRetryNotFound(t *testing.T) {
+	emptyResponse := `<?xml version="1.0" encoding="UTF-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom">
+  <opensearch:totalResults xmlns:opensearch="http://a9.com/-/spec/opensearch/1.1/">0</opensearch:totalResults>
+</feed>`
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Not yet indexed for the first two requests
+		if requests.Add(1) <= 2 {
+			w.Write([]byte(emptyResponse))
+			return
+		}
+		w.Write([]byte(mockXMLResponse))
+	}))
+	defer server.Close()
+
+	opts := ClientOptions{RetryAttempts: 3, RetryDelay: time.Millisecond, RateLimit: time.Nanosecond}
+
+	// Fail fast by default
+	client := NewClientWithOptions(opts)
+	client.baseURL = server.URL
+	if _, err := client.GetByID(context.Background(), "1234.5678"); !IsNotFound(err) {
+		t.Errorf("Expected a not found error, got %v", err)
+	}
+	if n := requests.Load(); n != 1 {
+		t.Errorf("Expected a single request without RetryNotFound, got %d", n)
+	}
+
+	requests.Store(0)
+	opts.RetryNotFound = true
+	client = NewClientWithOptions(opts)
+	client.baseURL = server.URL
+	paper, err := client.GetByID(context.Background(), "1234.5678")
+	if err != nil {
+		t.Fatalf("Expected the paper once indexed, got %v", err)
+	}
+	if paper.ID != "1234.5678v1" {
+		t.Errorf("Expected paper 1234.5678v1, got %s", paper.ID)
+	}
+	if n := requests.Load(); n != 3 {
+		t.Errorf("Expected 3 requests, got %d", n)
+	}
+
+	// Retries are bounded by RetryAttempts
+	requests.Store(-10)
+	_, err = client.GetByID(context.Background(), "1234.5678")
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.Type != ErrorTypeNotFound || apiErr.Attempts != 3 {
+		t.Errorf("Expected a not found error after 3 attempts, got %v", err)
+	}
+	if apiErr != nil && !strings.Contains(apiErr.Message, "1234.5678") {
+		t.Errorf("Expected the error to name the ID, got %q", apiErr.Message)
+	}
+}
+
+func TestSearchHTTPNotFound(t *testing.T) {
+	// A 404 means a wrong URL or a proxy page rather than a missing paper
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	client := NewClientWithOptions(ClientOptions{RetryAttempts: 2, RetryDelay: time.Millisecond, RateLimit: time.Nanosecond})
+	client.baseURL = server.URL
+	_, err := client.Search(context.Background(), &Query{SearchQuery: "test", MaxResults: 1})
+	if err == nil || IsNotFound(err) || !errors.Is(err, ErrNetwork) {
+		t.Errorf("Expected a network error, got %v", err)
+	}
+	if n := requests.Load(); n != 2 {
+		t.Errorf("Expected the 404 to be retried, got %d requests", n)
+	}
+}
+
+func TestGetByIDNotFoundConcurrent(t *testing.T) {
+	emptyResponse := `<?xml version="1.0" encoding="UTF-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom">
+  <opensearch:totalResults xmlns:opensearch="http://a9.com/-/spec/opensearch/1.1/">0</opensearch:totalResults>
+</feed>`
+	var requests atomic.Int32
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		<-release
+		w.Write([]byte(emptyResponse))
+	}))
+	defer server.Close()
+
+	client := NewClientWithOptions(ClientOptions{RateLimit: time.Nanosecond, Singleflight: true})
+	client.baseURL = server.URL
+
+	// Every caller gets the not found error of the shared search; run with -race
+	const callers = 4
+	errs := make([]error, callers)
+	var wg sync.WaitGroup
+	for i := range callers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, errs[i] = client.GetByID(context.Background(), "2301.99999")
+		}()
+	}
+	awaitFlight(&requests)
+	close(release)
+	wg.Wait()
+
+	for i, err := range errs {
+		if !IsNotFound(err) || !strings.Contains(err.Error(), "paper with ID 2301.99999 not found") {
+			t.Errorf("Expected caller %d to get a not found error naming the ID, got %v", i, err)
+		}
+	}
+	if errs[0] == errs[1] {
+		t.Error("Expected each caller to get its own error")
+	}
+}
+
+func TestSearchNetworkError(t *testing.T) {
+	// Create client with invalid URL to trigger network error
+	client := NewClient()
+	client.baseURL = "http://invalid-url-that-does-not-exist"
+
+	query := &Query{
+		SearchQuery: "test",
+		MaxResults:  1,
+	}
+
+	_, err := client.Search(context.Background(), query)
+	if err == nil {
+		t.Error("Expected network error, got nil")
+	}
+
+	apiErr, ok := err.(*APIError)
+	if !ok {
+		t.Error("Expected APIError type")
+	}
+
+	if apiErr.Type != ErrorTypeNetwork {
+		t.Errorf("Expected ErrorTypeNetwork, got %v", apiErr.Type)
+	}
+}
+
+func TestSearchTimeoutError(t *testing.T) {
+	// Server slower than the client timeout
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+		w.Header().Set("Content-Type", "application/atom+xml")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(mockXMLResponse))
+	}))
+	defer server.Close()
+
+	client := NewClientWithOptions(ClientOptions{
+		Timeout:       20 * time.Millisecond,
+		RateLimit:     time.Nanosecond,
+		RetryAttempts: 1,
+	})
+	client.baseURL = server.URL
+
+	query := &Query{
+		SearchQuery: "test",
+		MaxResults:  1,
+	}
+
+	_, err := client.Search(context.Background(), query)
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("Expected APIError, got %v", err)
+	}
+	if apiErr.Type != ErrorTypeTimeout {
+		t.Errorf("Expected ErrorTypeTimeout, got %v", apiErr.Type)
+	}
+	if !apiErr.Retry {
+		t.Error("Expected timeout error to be retryable")
+	}
+
+	// A refused connection is a network error, not a timeout
+	server.Close()
+	_, err = client.Search(context.Background(), query)
+	if !errors.As(err, &apiErr) || apiErr.Type != ErrorTypeNetwork {
+		t.Errorf("Expected ErrorTypeNetwork for refused connection, got %v", err)
+	}
+}
+
+// =============================================================================
+// Retry Mechanism Tests
+// =============================================================================
+
+func TestSearchWithRetryRateLimit(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts <= 2 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Header().Set("Content-Type", "application/atom+xml")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(mockXMLResponse))
+	}))
+	defer server.Close()
 
 	client := NewClientWithOptions(ClientOptions{
 		RetryAttempts: 3,
@@ -424,21 +1448,231 @@ func TestSearchWithRetryRateLimit(t *testing.T) {
 	}
 
 	_, err := client.Search(context.Background(), query)
-	if err != nil {
-		t.Fatalf("Expected success after retries, got error: %v", err)
+	if err != nil {
+		t.Fatalf("Expected success after retries, got error: %v", err)
+	}
+
+	if attempts != 3 {
+		t.Errorf("Expected 3 attempts, got %d", attempts)
+	}
+}
+
+func TestSearchWithRetryServerError(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts <= 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/atom+xml")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(mockXMLResponse))
+	}))
+	defer server.Close()
+
+	client := NewClientWithOptions(ClientOptions{
+		RetryAttempts: 3,
+		RetryDelay:    10 * time.Millisecond,
+		RateLimit:     1 * time.Millisecond,
+	})
+	client.baseURL = server.URL
+
+	query := &Query{
+		SearchQuery: "test",
+		MaxResults:  1,
+	}
+
+	_, err := client.Search(context.Background(), query)
+	if err != nil {
+		t.Fatalf("Expected success after retries, got error: %v", err)
+	}
+
+	if attempts != 2 {
+		t.Errorf("Expected 2 attempts, got %d", attempts)
+	}
+}
+
+func TestSearchWithRetryTruncatedBody(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.Header().Set("Content-Type", "application/atom+xml")
+		w.WriteHeader(http.StatusOK)
+		if attempts <= 1 {
+			// Connection closed mid-stream
+			w.Write([]byte(mockXMLResponse[:len(mockXMLResponse)/2]))
+			return
+		}
+		w.Write([]byte(mockXMLResponse))
+	}))
+	defer server.Close()
+
+	client := NewClientWithOptions(ClientOptions{
+		RetryAttempts: 3,
+		RetryDelay:    10 * time.Millisecond,
+		RateLimit:     1 * time.Millisecond,
+	})
+	client.baseURL = server.URL
+
+	query := &Query{
+		SearchQuery: "test",
+		MaxResults:  1,
+	}
+
+	_, err := client.Search(context.Background(), query)
+	if err != nil {
+		t.Fatalf("Expected success after retries, got error: %v", err)
+	}
+
+	if attempts != 2 {
+		t.Errorf("Expected 2 attempts, got %d", attempts)
+	}
+}
+
+func TestSearchMalformedXMLNotRetried(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.Header().Set("Content-Type", "application/atom+xml")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`<feed xmlns="http://www.w3.org/2005/Atom"><entry></feed>`))
+	}))
+	defer server.Close()
+
+	client := NewClientWithOptions(ClientOptions{
+		RetryAttempts: 3,
+		RetryDelay:    1 * time.Millisecond,
+		RateLimit:     1 * time.Millisecond,
+	})
+	client.baseURL = server.URL
+
+	query := &Query{
+		SearchQuery: "test",
+		MaxResults:  1,
+	}
+
+	_, err := client.Search(context.Background(), query)
+	if !errors.Is(err, ErrParsing) {
+		t.Fatalf("Expected parsing error, got %v", err)
 	}
 
-	if attempts != 3 {
-		t.Errorf("Expected 3 attempts, got %d", attempts)
+	if attempts != 1 {
+		t.Errorf("Expected malformed XML not to be retried, got %d attempts", attempts)
+	}
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.Attempts != 0 {
+		t.Errorf("Expected Attempts to stay zero for a single-shot failure, got %v", err)
 	}
 }
 
-func TestSearchWithRetryServerError(t *testing.T) {
+func TestIsTransient(t *testing.T) {
+	serve := func(body string) error {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(body))
+		}))
+		defer server.Close()
+
+		client := NewClientWithOptions(ClientOptions{RetryAttempts: 1, RetryDelay: time.Millisecond, RateLimit: time.Nanosecond})
+		client.baseURL = server.URL
+		_, err := client.Search(context.Background(), &Query{SearchQuery: "test", MaxResults: 1})
+		return err
+	}
+
+	truncated := serve(mockXMLResponse[:len(mockXMLResponse)/2])
+	if !errors.Is(truncated, ErrParsing) || !errors.Is(truncated, ErrTruncatedResponse) {
+		t.Errorf("Expected a truncated response parsing error, got %v", truncated)
+	}
+	if !IsTransient(truncated) {
+		t.Errorf("Expected a truncated feed to be transient, got %v", truncated)
+	}
+
+	malformed := serve(`<feed xmlns="http://www.w3.org/2005/Atom"><entry></feed>`)
+	if !errors.Is(malformed, ErrParsing) || errors.Is(malformed, ErrTruncatedResponse) {
+		t.Errorf("Expected a malformed feed parsing error, got %v", malformed)
+	}
+	if IsTransient(malformed) {
+		t.Errorf("Expected a malformed feed not to be transient, got %v", malformed)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	tests := []struct {
+		err       error
+		transient bool
+	}{
+		{NewAPIError(ErrorTypeRateLimit, "slow down", nil), true},
+		{NewAPIError(ErrorTypeNetwork, "connection reset", nil), true},
+		{NewAPIError(ErrorTypeServerError, "circuit breaker open", nil), true},
+		{NewAPIError(ErrorTypeInvalidQuery, "bad query", nil), false},
+		{NewAPIError(ErrorTypeNotFound, "no such paper", nil), false},
+		{newContextError(ctx), false},
+		{errors.New("plain error"), false},
+		{nil, false},
+	}
+	for _, tt := range tests {
+		if got := IsTransient(tt.err); got != tt.transient {
+			t.Errorf("IsTransient(%v) = %v, expected %v", tt.err, got, tt.transient)
+		}
+	}
+}
+
+func TestSearchBadRequest(t *testing.T) {
+	const errorFeed = `<?xml version="1.0" encoding="UTF-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom">
+  <title xmlns="http://www.w3.org/2005/Atom">ArXiv Query: search_query=&amp;id_list=1234.12345</title>
+  <entry>
+    <id>http://arxiv.org/api/errors#incorrect_id_format_for_1234.12345</id>
+    <title>Error</title>
+    <summary>incorrect id format for 1234.12345</summary>
+    <link href="http://arxiv.org/api/errors#incorrect_id_format_for_1234.12345" rel="alternate" type="text/html"/>
+  </entry>
+</feed>`
+
+	tests := []struct {
+		name     string
+		body     string
+		expected string
+	}{
+		{"atom error feed", errorFeed, "invalid query: incorrect id format for 1234.12345"},
+		{"plain text", "max_results must be non-negative\n", "invalid query: max_results must be non-negative"},
+		{"html page", "<html><body><h1>Bad Request</h1></body></html>", "invalid query"},
+		{"empty body", "", "invalid query"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attempts := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				attempts++
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			client := newFastClient(server.URL)
+			_, err := client.Search(context.Background(), &Query{IDList: []string{"1234.12345"}})
+
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) || apiErr.Type != ErrorTypeInvalidQuery {
+				t.Fatalf("Expected ErrorTypeInvalidQuery, got %v", err)
+			}
+			if apiErr.Message != tt.expected {
+				t.Errorf("Expected message %q, got %q", tt.expected, apiErr.Message)
+			}
+			if attempts != 1 {
+				t.Errorf("Expected a bad request not to be retried, got %d attempts", attempts)
+			}
+		})
+	}
+}
+
+func TestClientStats(t *testing.T) {
 	attempts := 0
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		attempts++
-		if attempts <= 1 {
-			w.WriteHeader(http.StatusInternalServerError)
+		if attempts == 1 {
+			w.WriteHeader(http.StatusTooManyRequests)
 			return
 		}
 		w.Header().Set("Content-Type", "application/atom+xml")
@@ -449,7 +1683,7 @@ func TestSearchWithRetryServerError(t *testing.T) {
 
 	client := NewClientWithOptions(ClientOptions{
 		RetryAttempts: 3,
-		RetryDelay:    10 * time.Millisecond,
+		RetryDelay:    1 * time.Millisecond,
 		RateLimit:     1 * time.Millisecond,
 	})
 	client.baseURL = server.URL
@@ -459,13 +1693,35 @@ func TestSearchWithRetryServerError(t *testing.T) {
 		MaxResults:  1,
 	}
 
-	_, err := client.Search(context.Background(), query)
-	if err != nil {
-		t.Fatalf("Expected success after retries, got error: %v", err)
+	for range 2 {
+		if _, err := client.Search(context.Background(), query); err != nil {
+			t.Fatalf("Search failed: %v", err)
+		}
 	}
 
-	if attempts != 2 {
-		t.Errorf("Expected 2 attempts, got %d", attempts)
+	stats := client.Stats()
+	if stats.Requests != 3 {
+		t.Errorf("Expected 3 requests, got %d", stats.Requests)
+	}
+	if stats.Retries != 1 {
+		t.Errorf("Expected 1 retry, got %d", stats.Retries)
+	}
+	if stats.RateLimitHits != 1 {
+		t.Errorf("Expected 1 rate limit hit, got %d", stats.RateLimitHits)
+	}
+	if stats.BytesRead != int64(2*len(mockXMLResponse)) {
+		t.Errorf("Expected %d bytes read, got %d", 2*len(mockXMLResponse), stats.BytesRead)
+	}
+	if len(stats.Errors) != 1 || stats.Errors[ErrorTypeRateLimit] != 1 {
+		t.Errorf("Expected one rate limit error, got %v", stats.Errors)
+	}
+
+	// Snapshots are independent of later requests
+	if _, err := client.Search(context.Background(), query); err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if stats.Requests != 3 || client.Stats().Requests != 4 {
+		t.Errorf("Expected snapshot to stay at 3 and client to report 4, got %d and %d", stats.Requests, client.Stats().Requests)
 	}
 }
 
@@ -501,6 +1757,48 @@ func TestSearchRetryExhaustion(t *testing.T) {
 	if apiErr.Type != ErrorTypeRateLimit {
 		t.Errorf("Expected ErrorTypeRateLimit, got %v", apiErr.Type)
 	}
+	if apiErr.Attempts != 2 {
+		t.Errorf("Expected Attempts to equal RetryAttempts (2), got %d", apiErr.Attempts)
+	}
+}
+
+func TestSearchMaxElapsedRetryTime(t *testing.T) {
+	// Server fails slowly with a retryable error
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		time.Sleep(100 * time.Millisecond)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client := NewClientWithOptions(ClientOptions{
+		RetryAttempts:       10,
+		RetryDelay:          50 * time.Millisecond,
+		RateLimit:           1 * time.Millisecond,
+		MaxElapsedRetryTime: 300 * time.Millisecond,
+	})
+	client.baseURL = server.URL
+
+	start := time.Now()
+	_, err := client.Search(context.Background(), &Query{SearchQuery: "test", MaxResults: 1})
+	elapsed := time.Since(start)
+
+	if !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("Expected the last rate limit error, got %v", err)
+	}
+	// Attempts start at about 0, 100, 250ms; a fourth would start past the budget
+	if n := requests.Load(); n < 2 || n >= 10 {
+		t.Errorf("Expected the budget to stop retries early, got %d requests", n)
+	}
+	if elapsed > 600*time.Millisecond {
+		t.Errorf("Expected Search to give up within about the budget plus one attempt, took %v", elapsed)
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.Attempts != int(requests.Load()) {
+		t.Errorf("Expected Attempts %d, got %d", requests.Load(), apiErr.Attempts)
+	}
 }
 
 func TestSearchContextCancellation(t *testing.T) {
@@ -530,8 +1828,260 @@ func TestSearchContextCancellation(t *testing.T) {
 		t.Error("Expected context cancellation error")
 	}
 
-	if err != context.DeadlineExceeded {
-		t.Errorf("Expected context.DeadlineExceeded, got %v", err)
+	apiErr, ok := err.(*APIError)
+	if !ok {
+		t.Fatalf("Expected APIError type, got %T", err)
+	}
+
+	if apiErr.Type != ErrorTypeTimeout || apiErr.Retry {
+		t.Errorf("Expected non-retryable ErrorTypeTimeout, got %v (retry %v)", apiErr.Type, apiErr.Retry)
+	}
+
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected errors.Is(err, context.DeadlineExceeded), got %v", err)
+	}
+}
+
+func TestSearchContextCanceledDuringRequest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(100 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := newFastClient(server.URL)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(10*time.Millisecond, cancel)
+
+	_, err := client.Search(ctx, &Query{SearchQuery: "test", MaxResults: 1})
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.Type != ErrorTypeTimeout {
+		t.Fatalf("Expected ErrorTypeTimeout APIError, got %v", err)
+	}
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected errors.Is(err, context.Canceled), got %v", err)
+	}
+}
+
+func TestSearchQueryTimeout(t *testing.T) {
+	// Server slower than the per-query timeout but faster than everything else
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+		w.Header().Set("Content-Type", "application/atom+xml")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(mockXMLResponse))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name          string
+		clientTimeout time.Duration
+		ctxTimeout    time.Duration
+		queryTimeout  time.Duration
+		expectError   bool
+	}{
+		{"query timeout most restrictive", 5 * time.Second, 5 * time.Second, 20 * time.Millisecond, true},
+		{"context deadline most restrictive", 5 * time.Second, 20 * time.Millisecond, 5 * time.Second, true},
+		{"client timeout most restrictive", 20 * time.Millisecond, 5 * time.Second, 5 * time.Second, true},
+		{"all timeouts generous", 5 * time.Second, 5 * time.Second, 5 * time.Second, false},
+		{"no query timeout", 5 * time.Second, 5 * time.Second, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := NewClientWithOptions(ClientOptions{
+				Timeout:       tt.clientTimeout,
+				RateLimit:     time.Nanosecond,
+				RetryAttempts: 1,
+			})
+			client.baseURL = server.URL
+
+			ctx, cancel := context.WithTimeout(context.Background(), tt.ctxTimeout)
+			defer cancel()
+
+			query := &Query{
+				SearchQuery: "test",
+				MaxResults:  1,
+				Timeout:     tt.queryTimeout,
+			}
+
+			start := time.Now()
+			_, err := client.Search(ctx, query)
+			elapsed := time.Since(start)
+
+			if tt.expectError {
+				if err == nil {
+					t.Fatal("Expected timeout error")
+				}
+				if elapsed >= 200*time.Millisecond {
+					t.Errorf("Expected the most restrictive timeout to abort early, took %v", elapsed)
+				}
+			} else if err != nil {
+				t.Errorf("Expected no error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestSearchQueryTimeoutExcludesRateLimit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/atom+xml")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(mockXMLResponse))
+	}))
+	defer server.Close()
+
+	client := NewClientWithOptions(ClientOptions{
+		RateLimit:     100 * time.Millisecond,
+		RetryAttempts: 1,
+	})
+	client.baseURL = server.URL
+
+	// The rate limit wait is longer than the per-request timeout
+	query := &Query{
+		SearchQuery: "test",
+		MaxResults:  1,
+		Timeout:     50 * time.Millisecond,
+	}
+
+	if _, err := client.Search(context.Background(), query); err != nil {
+		t.Errorf("Expected rate limit wait not to count against Query.Timeout, got %v", err)
+	}
+}
+
+func TestSearchPooledBuffersDoNotAlias(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "application/atom+xml")
+		w.WriteHeader(http.StatusOK)
+		if requests == 1 {
+			w.Write([]byte(mockXMLResponse))
+			return
+		}
+		w.Write([]byte(strings.ReplaceAll(mockXMLResponse, "Quantum Computing", "Something Else Entirely")))
+	}))
+	defer server.Close()
+
+	client := NewClientWithOptions(ClientOptions{RateLimit: time.Nanosecond})
+	client.baseURL = server.URL
+
+	query := &Query{
+		SearchQuery: "test",
+		MaxResults:  1,
+	}
+
+	first, err := client.Search(context.Background(), query)
+	if err != nil {
+		t.Fatalf("First search failed: %v", err)
+	}
+
+	if _, err := client.Search(context.Background(), query); err != nil {
+		t.Fatalf("Second search failed: %v", err)
+	}
+
+	if first.Papers[0].Title != "Test Paper on Quantum Computing" {
+		t.Errorf("First results were modified by buffer reuse: title '%s'", first.Papers[0].Title)
+	}
+}
+
+func BenchmarkSearch(b *testing.B) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/atom+xml")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(mockXMLResponse))
+	}))
+	defer server.Close()
+
+	client := NewClientWithOptions(ClientOptions{RateLimit: time.Nanosecond})
+	client.baseURL = server.URL
+
+	query := &Query{
+		SearchQuery: "test",
+		MaxResults:  1,
+	}
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := client.Search(context.Background(), query); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestSearchNoMatches(t *testing.T) {
+	server := newPagingServer(0)
+	defer server.Close()
+
+	client := newFastClient(server.URL)
+	query := &Query{SearchQuery: "nothing", MaxResults: 10}
+
+	// By default no matches is an empty result, not an error
+	results, err := client.Search(context.Background(), query)
+	if err != nil {
+		t.Fatalf("Expected no error for a query without matches, got %v", err)
+	}
+	if !results.IsEmpty() || results.TotalCount != 0 {
+		t.Errorf("Expected empty results, got %d papers of %d", len(results.Papers), results.TotalCount)
+	}
+
+	query.ErrorOnEmpty = true
+	results, err = client.Search(context.Background(), query)
+	if !IsNotFound(err) || results != nil {
+		t.Errorf("Expected a not found error with ErrorOnEmpty, got %v, %v", results, err)
+	}
+
+	// Results with papers are unaffected
+	full := newPagingServer(3)
+	defer full.Close()
+	results, err = newFastClient(full.URL).Search(context.Background(), query)
+	if err != nil || results.IsEmpty() {
+		t.Errorf("Expected papers with ErrorOnEmpty set, got %v, %v", results, err)
+	}
+}
+
+func TestSearchSkipAbstract(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/atom+xml")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(mockXMLResponse))
+	}))
+	defer server.Close()
+
+	client := newFastClient(server.URL)
+	results, err := client.Search(context.Background(), &Query{SearchQuery: "test", MaxResults: 1, SkipAbstract: true})
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+
+	paper := results.Papers[0]
+	if paper.Abstract != "" {
+		t.Errorf("Expected empty abstract, got '%s'", paper.Abstract)
+	}
+	if paper.ID != "1234.5678v1" || paper.Title != "Test Paper on Quantum Computing" || len(paper.Authors) != 2 {
+		t.Errorf("Expected the other fields to be parsed, got %+v", paper)
+	}
+	if results.TotalCount != 50000 {
+		t.Errorf("Expected feed metadata to be parsed, got total %d", results.TotalCount)
+	}
+}
+
+func BenchmarkParseSearchResponse(b *testing.B) {
+	// A large page with realistically sized abstracts
+	feed := []byte(strings.ReplaceAll(generateFeed(1000, 0, 1000), "<summary>", "<summary>"+strings.Repeat("We study the asymptotic behavior of the model. ", 25)))
+	client := NewClient()
+
+	for _, skipAbstract := range []bool{false, true} {
+		b.Run(fmt.Sprintf("SkipAbstract=%v", skipAbstract), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := client.parseSearchResponse(feed, skipAbstract); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
 	}
 }
 
@@ -539,6 +2089,58 @@ func TestSearchContextCancellation(t *testing.T) {
 // Rate Limiting Tests
 // =============================================================================
 
+func TestSearchMaxResponseBytes(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/atom+xml")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(mockXMLResponse))
+	}))
+	defer server.Close()
+
+	query := &Query{SearchQuery: "test", MaxResults: 1}
+
+	// Limit smaller than the response body
+	client := NewClientWithOptions(ClientOptions{
+		RateLimit:        time.Nanosecond,
+		MaxResponseBytes: int64(len(mockXMLResponse) - 1),
+	})
+	client.baseURL = server.URL
+
+	_, err := client.Search(context.Background(), query)
+	if !errors.Is(err, ErrResponseTooLarge) {
+		t.Fatalf("Expected ErrResponseTooLarge, got %v", err)
+	}
+	if !errors.Is(err, ErrParsing) || IsRetryable(err) {
+		t.Errorf("Expected a non-retryable parsing error, got %v", err)
+	}
+
+	// Limit exactly the size of the response body
+	client = NewClientWithOptions(ClientOptions{
+		RateLimit:        time.Nanosecond,
+		MaxResponseBytes: int64(len(mockXMLResponse)),
+	})
+	client.baseURL = server.URL
+
+	if _, err := client.Search(context.Background(), query); err != nil {
+		t.Errorf("Expected response at the limit to succeed, got %v", err)
+	}
+
+	// Negative limit disables the cap
+	client = NewClientWithOptions(ClientOptions{
+		RateLimit:        time.Nanosecond,
+		MaxResponseBytes: -1,
+	})
+	client.baseURL = server.URL
+
+	if _, err := client.Search(context.Background(), query); err != nil {
+		t.Errorf("Expected unlimited client to succeed, got %v", err)
+	}
+
+	if NewClient().options.MaxResponseBytes != defaultMaxResponseBytes {
+		t.Errorf("Expected default MaxResponseBytes %d, got %d", defaultMaxResponseBytes, NewClient().options.MaxResponseBytes)
+	}
+}
+
 func TestRateLimiting(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/atom+xml")
@@ -665,6 +2267,83 @@ func TestConcurrentRateLimiting(t *testing.T) {
 	}
 }
 
+func TestConcurrentClientStress(t *testing.T) {
+	const rateLimit = 5 * time.Millisecond
+	var mu sync.Mutex
+	var sent []time.Time
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		mu.Lock()
+		sent = append(sent, time.Now())
+		mu.Unlock()
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader(mockXMLResponse)),
+			Request:    req,
+		}, nil
+	})
+	client := NewClientWithOptionsAndHTTPClient(ClientOptions{RateLimit: rateLimit}, &http.Client{Transport: transport})
+
+	// The first request doesn't wait for the rate limit
+	slow := NewClientWithOptionsAndHTTPClient(ClientOptions{RateLimit: time.Minute}, &http.Client{Transport: transport})
+	start := time.Now()
+	if _, err := slow.GetByID(context.Background(), "1234.5678"); err != nil {
+		t.Fatalf("GetByID failed: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Errorf("Expected the first request to go out immediately, took %v", elapsed)
+	}
+	mu.Lock()
+	sent = nil
+	mu.Unlock()
+
+	// Searches, lookups and iterators share the client
+	const goroutines = 36
+	errs := make(chan error, goroutines)
+	var wg sync.WaitGroup
+	for i := range goroutines {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var err error
+			switch i % 3 {
+			case 0:
+				_, err = client.Search(context.Background(), &Query{SearchQuery: fmt.Sprintf("all:test%d", i), MaxResults: 1})
+			case 1:
+				_, err = client.GetByID(context.Background(), "1234.5678")
+			default:
+				_, err = client.NewQuery().SearchQuery("quantum").Limit(1).Iterator(context.Background()).Collect()
+			}
+			if err != nil {
+				errs <- err
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Errorf("Concurrent request failed: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(sent) != goroutines {
+		t.Fatalf("Expected %d requests, got %d", goroutines, len(sent))
+	}
+	if stats := client.Stats(); stats.Requests != goroutines {
+		t.Errorf("Expected Stats to count %d requests, got %d", goroutines, stats.Requests)
+	}
+	slices.SortFunc(sent, func(a, b time.Time) int { return a.Compare(b) })
+	for i := 1; i < len(sent); i++ {
+		// Allow for the time between the rate limiter and the transport
+		if gap := sent[i].Sub(sent[i-1]); gap < rateLimit/2 {
+			t.Errorf("Requests %d and %d went out %v apart, below the rate limit of %v", i-1, i, gap, rateLimit)
+		}
+	}
+	if span := sent[len(sent)-1].Sub(sent[0]); span < (goroutines-2)*rateLimit {
+		t.Errorf("Expected %d requests to span at least %v, got %v", goroutines, (goroutines-2)*rateLimit, span)
+	}
+}
+
 // =============================================================================
 // Query Building Tests
 // =============================================================================
@@ -760,6 +2439,50 @@ func TestBuildQueryParams(t *testing.T) {
 	}
 }
 
+func TestQueryURL(t *testing.T) {
+	var requested string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = "http://" + r.Host + r.URL.String()
+		w.Header().Set("Content-Type", "application/atom+xml")
+		w.Write([]byte(mockXMLResponse))
+	}))
+	defer server.Close()
+
+	client := NewClient()
+	client.baseURL = server.URL + "/api/query"
+
+	from := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	query := &Query{
+		SearchQuery:       "au:Hinton AND ti:\"deep learning\"",
+		Start:             20,
+		MaxResults:        10,
+		SortBy:            string(SortBySubmittedDate),
+		SubmittedDateFrom: &from,
+	}
+
+	got, err := client.QueryURL(query)
+	if err != nil {
+		t.Fatalf("QueryURL failed: %v", err)
+	}
+	if _, err := client.Search(context.Background(), query); err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if got != requested {
+		t.Errorf("Expected QueryURL to match the requested URL\n  QueryURL:  %s\n  requested: %s", got, requested)
+	}
+
+	if !strings.HasPrefix(got, client.baseURL+"?") {
+		t.Errorf("Expected URL to start with the base URL, got %s", got)
+	}
+
+	if _, err := client.QueryURL(nil); !errors.Is(err, ErrInvalidQuery) {
+		t.Errorf("Expected ErrInvalidQuery for nil query, got %v", err)
+	}
+	if _, err := client.QueryURL(&Query{SearchQuery: "x", SortBy: "newest"}); !errors.Is(err, ErrInvalidQuery) {
+		t.Errorf("Expected ErrInvalidQuery for invalid sort, got %v", err)
+	}
+}
+
 func TestBuildDateRangeFilter(t *testing.T) {
 	client := NewClient()
 
@@ -859,6 +2582,192 @@ func TestSearchDateRangeOnly(t *testing.T) {
 	}
 }
 
+func TestSearchSince(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if sortBy := r.URL.Query().Get("sortBy"); sortBy != "submittedDate" {
+			t.Errorf("Expected sortBy 'submittedDate', got '%s'", sortBy)
+		}
+		if sortOrder := r.URL.Query().Get("sortOrder"); sortOrder != "descending" {
+			t.Errorf("Expected sortOrder 'descending', got '%s'", sortOrder)
+		}
+
+		response := `<?xml version="1.0" encoding="UTF-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom">
+  <opensearch:totalResults xmlns:opensearch="http://a9.com/-/spec/opensearch/1.1/">4</opensearch:totalResults>
+  <opensearch:startIndex xmlns:opensearch="http://a9.com/-/spec/opensearch/1.1/">0</opensearch:startIndex>
+  <opensearch:itemsPerPage xmlns:opensearch="http://a9.com/-/spec/opensearch/1.1/">4</opensearch:itemsPerPage>
+  <entry>
+    <id>http://arxiv.org/abs/2301.00004v1</id>
+    <title>Newest</title>
+    <published>2023-01-04T00:00:00Z</published>
+    <updated>2023-01-04T00:00:00Z</updated>
+  </entry>
+  <entry>
+    <id>http://arxiv.org/abs/2301.00003v1</id>
+    <title>Newer</title>
+    <published>2023-01-03T00:00:00Z</published>
+    <updated>2023-01-03T00:00:00Z</updated>
+  </entry>
+  <entry>
+    <id>http://arxiv.org/abs/2301.00002v1</id>
+    <title>Boundary</title>
+    <published>2023-01-02T00:00:00Z</published>
+    <updated>2023-01-02T00:00:00Z</updated>
+  </entry>
+  <entry>
+    <id>http://arxiv.org/abs/2301.00001v1</id>
+    <title>Older</title>
+    <published>2023-01-01T00:00:00Z</published>
+    <updated>2023-01-01T00:00:00Z</updated>
+  </entry>
+</feed>`
+		w.Header().Set("Content-Type", "application/atom+xml")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(response))
+	}))
+	defer server.Close()
+
+	client := NewClientWithOptions(ClientOptions{RateLimit: time.Nanosecond})
+	client.baseURL = server.URL
+
+	query := &Query{SearchQuery: "cat:cs.LG", MaxResults: 10}
+	since := time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	papers, err := client.SearchSince(context.Background(), query, since)
+	if err != nil {
+		t.Fatalf("SearchSince failed: %v", err)
+	}
+
+	if len(papers) != 2 {
+		t.Fatalf("Expected 2 papers after the checkpoint, got %d", len(papers))
+	}
+	if papers[0].Title != "Newest" || papers[1].Title != "Newer" {
+		t.Errorf("Expected [Newest Newer], got [%s %s]", papers[0].Title, papers[1].Title)
+	}
+
+	if query.SortBy != "" {
+		t.Errorf("Expected caller's query to be left unchanged, got SortBy '%s'", query.SortBy)
+	}
+}
+
+func TestListRecent(t *testing.T) {
+	now := time.Now().UTC()
+	ages := []time.Duration{time.Hour, 20 * time.Hour, 30 * time.Hour, 50 * time.Hour}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		expectedQuery := fmt.Sprintf("(cat:cs.LG) AND submittedDate:[%s TO *]", now.Add(-24*time.Hour).Format("20060102"))
+		if searchQuery := r.URL.Query().Get("search_query"); searchQuery != expectedQuery {
+			t.Errorf("Expected search query '%s', got '%s'", expectedQuery, searchQuery)
+		}
+		if sortBy := r.URL.Query().Get("sortBy"); sortBy != "submittedDate" {
+			t.Errorf("Expected sortBy 'submittedDate', got '%s'", sortBy)
+		}
+
+		// Like arXiv, the mock matches the date by day and returns a paper before the window
+		var b strings.Builder
+		fmt.Fprintf(&b, `<?xml version="1.0" encoding="UTF-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom" xmlns:opensearch="http://a9.com/-/spec/opensearch/1.1/">
+  <opensearch:totalResults>%d</opensearch:totalResults>
+  <opensearch:startIndex>0</opensearch:startIndex>
+  <opensearch:itemsPerPage>%d</opensearch:itemsPerPage>`, len(ages), len(ages))
+		for i, age := range ages {
+			published := now.Add(-age).Format(time.RFC3339)
+			fmt.Fprintf(&b, `
+  <entry>
+    <id>http://arxiv.org/abs/2301.%05dv1</id>
+    <title>Paper %d</title>
+    <published>%s</published>
+    <updated>%s</updated>
+  </entry>`, i, i, published, published)
+		}
+		b.WriteString("\n</feed>")
+		w.Write([]byte(b.String()))
+	}))
+	defer server.Close()
+
+	client := newFastClient(server.URL)
+	papers, err := client.ListRecent(context.Background(), CategoryCSLG, 24*time.Hour)
+	if err != nil {
+		t.Fatalf("ListRecent failed: %v", err)
+	}
+	if len(papers) != 2 {
+		t.Fatalf("Expected the 2 papers within the window, got %d", len(papers))
+	}
+	if papers[0].Title != "Paper 0" || papers[1].Title != "Paper 1" {
+		t.Errorf("Expected [Paper 0 Paper 1], got [%s %s]", papers[0].Title, papers[1].Title)
+	}
+
+	if _, err := client.ListRecent(context.Background(), "", time.Hour); !errors.Is(err, ErrInvalidQuery) {
+		t.Errorf("Expected ErrInvalidQuery for empty category, got %v", err)
+	}
+	if _, err := client.ListRecent(context.Background(), CategoryCSLG, 0); !errors.Is(err, ErrInvalidQuery) {
+		t.Errorf("Expected ErrInvalidQuery for zero window, got %v", err)
+	}
+}
+
+func TestDigest(t *testing.T) {
+	since := time.Date(2023, 1, 10, 0, 0, 0, 0, time.UTC)
+	// Newest first; paper 1 is served twice, as when new submissions shift the pages
+	days := []int{14, 13, 13, 12, 11, 9, 8}
+	ids := []int{0, 1, 1, 2, 3, 4, 5}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		expectedQuery := "(cat:cs.LG OR cat:stat.ML) AND submittedDate:[20230110 TO *]"
+		if searchQuery := r.URL.Query().Get("search_query"); searchQuery != expectedQuery {
+			t.Errorf("Expected search query '%s', got '%s'", expectedQuery, searchQuery)
+		}
+		if sortBy := r.URL.Query().Get("sortBy"); sortBy != "submittedDate" {
+			t.Errorf("Expected sortBy 'submittedDate', got '%s'", sortBy)
+		}
+
+		var b strings.Builder
+		fmt.Fprintf(&b, `<?xml version="1.0" encoding="UTF-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom" xmlns:opensearch="http://a9.com/-/spec/opensearch/1.1/">
+  <opensearch:totalResults>%d</opensearch:totalResults>
+  <opensearch:startIndex>0</opensearch:startIndex>
+  <opensearch:itemsPerPage>%d</opensearch:itemsPerPage>`, len(ids), len(ids))
+		for i, id := range ids {
+			fmt.Fprintf(&b, `
+  <entry>
+    <id>http://arxiv.org/abs/2301.%05dv1</id>
+    <title>Paper %d</title>
+    <published>2023-01-%02dT12:00:00Z</published>
+    <updated>2023-01-%02dT12:00:00Z</updated>
+  </entry>`, id, id, days[i], days[i])
+		}
+		b.WriteString("\n</feed>")
+		w.Write([]byte(b.String()))
+	}))
+	defer server.Close()
+
+	client := newFastClient(server.URL)
+	cats := []Category{CategoryCSLG, "", CategoryStatML}
+
+	papers, err := client.Digest(context.Background(), cats, since, 0)
+	if err != nil {
+		t.Fatalf("Digest failed: %v", err)
+	}
+	var got []string
+	for _, paper := range papers {
+		got = append(got, paper.Title)
+	}
+	if want := []string{"Paper 0", "Paper 1", "Paper 2", "Paper 3"}; !slices.Equal(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+
+	papers, err = client.Digest(context.Background(), cats, since, 2)
+	if err != nil {
+		t.Fatalf("Digest failed: %v", err)
+	}
+	if len(papers) != 2 || papers[1].Title != "Paper 1" {
+		t.Errorf("Expected the 2 newest papers, got %d", len(papers))
+	}
+
+	if _, err := client.Digest(context.Background(), []Category{""}, since, 0); !IsInvalidQuery(err) {
+		t.Errorf("Expected invalid query error for no categories, got %v", err)
+	}
+}
+
 // =============================================================================
 // Factory Method Tests
 // =============================================================================
@@ -919,6 +2828,220 @@ func TestIterator(t *testing.T) {
 // Utility Function Tests
 // =============================================================================
 
+func TestParseCategorySchemes(t *testing.T) {
+	feed := `<?xml version="1.0" encoding="UTF-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom" xmlns:arxiv="http://arxiv.org/schemas/atom">
+  <entry>
+    <id>http://arxiv.org/abs/1234.5678v1</id>
+    <updated>2023-01-01T00:00:00Z</updated>
+    <published>2023-01-01T00:00:00Z</published>
+    <title>Mixed Schemes</title>
+    <arxiv:ACM-class>I.2.6; H.3.3</arxiv:ACM-class>
+    <arxiv:MSC-class> 14J60 (Primary) 14F05 </arxiv:MSC-class>
+    <category term="math.AG" scheme="http://arxiv.org/schemas/atom"/>
+    <category term="14J60" scheme="http://www.ams.org/msc/"/>
+    <category term="cs.LG" scheme="http://arxiv.org/schemas/atom"/>
+    <category term="I.2.6" scheme="http://www.acm.org/class/"/>
+  </entry>
+</feed>`
+
+	results, err := NewClient().parseSearchResponse([]byte(feed), false)
+	if err != nil {
+		t.Fatalf("parseSearchResponse failed: %v", err)
+	}
+
+	paper := results.Papers[0]
+	if len(paper.Categories) != 2 || paper.Categories[0] != "math.AG" || paper.Categories[1] != "cs.LG" {
+		t.Errorf("Expected only arXiv categories [math.AG cs.LG], got %v", paper.Categories)
+	}
+	if paper.ACMClass != "I.2.6; H.3.3" {
+		t.Errorf("Expected ACM class 'I.2.6; H.3.3', got '%s'", paper.ACMClass)
+	}
+	if paper.MSCClass != "14J60 (Primary) 14F05" {
+		t.Errorf("Expected MSC class '14J60 (Primary) 14F05', got '%s'", paper.MSCClass)
+	}
+}
+
+func TestParseNormalizeWhitespace(t *testing.T) {
+	feed := `<?xml version="1.0" encoding="UTF-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom">
+  <entry>
+    <id>http://arxiv.org/abs/1234.5678v1</id>
+    <updated>2023-01-01T00:00:00Z</updated>
+    <published>2023-01-01T00:00:00Z</published>
+    <title>  A Title Spanning
+  Two  Lines </title>
+    <summary>
+  First line of the abstract.
+  Second	line.
+    </summary>
+  </entry>
+</feed>`
+
+	results, err := NewClient().parseSearchResponse([]byte(feed), false)
+	if err != nil {
+		t.Fatalf("parseSearchResponse failed: %v", err)
+	}
+	paper := results.Papers[0]
+	if paper.Title != "A Title Spanning Two Lines" {
+		t.Errorf("Expected normalized title, got %q", paper.Title)
+	}
+	if paper.Abstract != "First line of the abstract. Second line." {
+		t.Errorf("Expected normalized abstract, got %q", paper.Abstract)
+	}
+
+	// A zero ClientOptions normalizes too
+	results, err = NewClientWithOptions(ClientOptions{}).parseSearchResponse([]byte(feed), false)
+	if err != nil {
+		t.Fatalf("parseSearchResponse failed: %v", err)
+	}
+	if paper := results.Papers[0]; paper.Title != "A Title Spanning Two Lines" {
+		t.Errorf("Expected normalized title with zero options, got %q", paper.Title)
+	}
+
+	raw := NewClientWithOptions(ClientOptions{PreserveWhitespace: true})
+	results, err = raw.parseSearchResponse([]byte(feed), false)
+	if err != nil {
+		t.Fatalf("parseSearchResponse failed: %v", err)
+	}
+	if paper := results.Papers[0]; paper.Title != "A Title Spanning\n  Two  Lines" {
+		t.Errorf("Expected raw title with PreserveWhitespace, got %q", paper.Title)
+	}
+}
+
+func TestParseStrayAmpersands(t *testing.T) {
+	feed := `<?xml version="1.0" encoding="UTF-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom" xmlns:arxiv="http://arxiv.org/schemas/atom">
+  <title>ArXiv Query: search_query=all:QA&amp;id_list=</title>
+  <entry>
+    <id>http://arxiv.org/abs/1234.5678v1</id>
+    <updated>2023-01-01T00:00:00Z</updated>
+    <published>2023-01-01T00:00:00Z</published>
+    <title>Q&A Systems for R&D: &lt;Tags&gt; &amp; &#38; &#x26;</title>
+    <summary>Costs fell by 5% & more&nbsp;often.</summary>
+    <arxiv:comment>AT&T; 10 pages</arxiv:comment>
+  </entry>
+</feed>`
+
+	results, err := NewClient().parseSearchResponse([]byte(feed), false)
+	if err != nil {
+		t.Fatalf("parseSearchResponse failed on raw ampersands: %v", err)
+	}
+	paper := results.Papers[0]
+	if expected := "Q&A Systems for R&D: <Tags> & & &"; paper.Title != expected {
+		t.Errorf("Expected title %q, got %q", expected, paper.Title)
+	}
+	if expected := "Costs fell by 5% & more&nbsp;often."; paper.Abstract != expected {
+		t.Errorf("Expected abstract %q, got %q", expected, paper.Abstract)
+	}
+	if expected := "AT&T; 10 pages"; paper.Comment != expected {
+		t.Errorf("Expected comment %q, got %q", expected, paper.Comment)
+	}
+}
+
+func TestParseFeedEncodings(t *testing.T) {
+	const entry = `
+  <entry>
+    <id>http://arxiv.org/abs/1234.5678v1</id>
+    <updated>2023-01-01T00:00:00Z</updated>
+    <published>2023-01-01T00:00:00Z</published>
+    <title>Caf%s %s</title>
+    <author><name>Erwin Schr%sdinger</name></author>
+    <author><name>Jos%s Garc%sa</name></author>
+  </entry>
+</feed>`
+
+	tests := []struct {
+		name string
+		feed string
+	}{
+		{
+			"latin-1",
+			`<?xml version="1.0" encoding="ISO-8859-1"?>
+<feed xmlns="http://www.w3.org/2005/Atom">` + fmt.Sprintf(entry, "\xe9", "\x80", "\xf6", "\xe9", "\xed"),
+		},
+		{
+			"windows-1252",
+			`<?xml version="1.0" encoding="windows-1252"?>
+<feed xmlns="http://www.w3.org/2005/Atom">` + fmt.Sprintf(entry, "\xe9", "\x80", "\xf6", "\xe9", "\xed"),
+		},
+		{
+			"iso-8859-15",
+			`<?xml version="1.0" encoding="ISO-8859-15"?>
+<feed xmlns="http://www.w3.org/2005/Atom">` + fmt.Sprintf(entry, "\xe9", "\xa4", "\xf6", "\xe9", "\xed"),
+		},
+		{
+			"utf-8 with BOM",
+			"\xef\xbb\xbf" + `<?xml version="1.0" encoding="UTF-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom">` + fmt.Sprintf(entry, "é", "€", "ö", "é", "í"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, err := NewClient().parseSearchResponse([]byte(tt.feed), false)
+			if err != nil {
+				t.Fatalf("parseSearchResponse failed: %v", err)
+			}
+			paper := results.Papers[0]
+			if paper.Title != "Café €" {
+				t.Errorf("Expected title %q, got %q", "Café €", paper.Title)
+			}
+			if len(paper.Authors) != 2 || paper.Authors[0].Name != "Erwin Schrödinger" || paper.Authors[1].Name != "José García" {
+				t.Errorf("Expected accented author names, got %v", paper.Authors)
+			}
+		})
+	}
+
+	unsupported := `<?xml version="1.0" encoding="x-no-such-charset"?><feed xmlns="http://www.w3.org/2005/Atom"></feed>`
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Write([]byte(unsupported))
+	}))
+	defer server.Close()
+
+	client := NewClientWithOptions(ClientOptions{RetryAttempts: 3, RetryDelay: time.Millisecond, RateLimit: time.Nanosecond})
+	client.baseURL = server.URL
+	_, err := client.Search(context.Background(), &Query{SearchQuery: "test", MaxResults: 1})
+	var apiErr *APIError
+	if !errors.Is(err, ErrUnsupportedCharset) || !errors.As(err, &apiErr) || apiErr.Type != ErrorTypeParsing {
+		t.Fatalf("Expected an ErrUnsupportedCharset parse error, got %v", err)
+	}
+	if !strings.Contains(err.Error(), `"x-no-such-charset"`) {
+		t.Errorf("Expected the error to name the charset, got %v", err)
+	}
+	if n := requests.Load(); n != 1 {
+		t.Errorf("Expected an unsupported charset not to be retried, got %d requests", n)
+	}
+}
+
+func TestEscapeStrayAmpersands(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"no ampersands", "<a>text</a>", "<a>text</a>"},
+		{"raw ampersand", "<a>Q&A</a>", "<a>Q&amp;A</a>"},
+		{"trailing ampersand", "<a>x</a>&", "<a>x</a>&amp;"},
+		{"valid references", "&amp;&lt;&gt;&quot;&apos;&#38;&#x26;", "&amp;&lt;&gt;&quot;&apos;&#38;&#x26;"},
+		{"unterminated reference", "&amp &#38", "&amp;amp &amp;#38"},
+		{"html entity", "a&nbsp;b", "a&amp;nbsp;b"},
+		{"cdata", "<a><![CDATA[Q&A]]>R&D</a>", "<a><![CDATA[Q&A]]>R&amp;D</a>"},
+		{"comment", "<!-- Q&A -->&", "<!-- Q&A -->&amp;"},
+		{"unterminated cdata", "<![CDATA[Q&A", "<![CDATA[Q&A"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := string(escapeStrayAmpersands([]byte(tt.input))); got != tt.expected {
+				t.Errorf("escapeStrayAmpersands(%q): expected %q, got %q", tt.input, tt.expected, got)
+			}
+		})
+	}
+}
+
 func TestExtractArxivID(t *testing.T) {
 	tests := []struct {
 		input    string
@@ -940,6 +3063,42 @@ func TestExtractArxivID(t *testing.T) {
 			input:    "quant-ph/0301001",
 			expected: "quant-ph/0301001",
 		},
+		{
+			input:    "https://arxiv.org/abs/2301.00001v2",
+			expected: "2301.00001v2",
+		},
+		{
+			input:    "https://www.arxiv.org/abs/2301.00001/",
+			expected: "2301.00001",
+		},
+		{
+			input:    "http://export.arxiv.org/abs/1234.5678v1?context=cs",
+			expected: "1234.5678v1",
+		},
+		{
+			input:    "https://arxiv.org/pdf/1234.5678v1.pdf",
+			expected: "1234.5678v1",
+		},
+		{
+			input:    "https://arxiv.org/pdf/1234.5678v1#page=2",
+			expected: "1234.5678v1",
+		},
+		{
+			input:    "HTTPS://ARXIV.ORG/abs/math.GT/0309136v1",
+			expected: "math.GT/0309136v1",
+		},
+		{
+			input:    "arxiv.org/abs/hep-th/9901001",
+			expected: "hep-th/9901001",
+		},
+		{
+			input:    "arXiv:2301.00001",
+			expected: "2301.00001",
+		},
+		{
+			input:    "  http://arxiv.org/abs/1234.5678v1\n",
+			expected: "1234.5678v1",
+		},
 	}
 
 	for _, tt := range tests {
@@ -951,3 +3110,52 @@ func TestExtractArxivID(t *testing.T) {
 		})
 	}
 }
+
+// validArxivIDPattern matches new-style and old-style arXiv IDs with an optional version
+var validArxivIDPattern = regexp.MustCompile(`^(?:\d{4}\.\d{4,5}|[a-z-]+(?:\.[A-Z]{2})?/\d{7})(?:v\d+)?$`)
+
+func FuzzExtractArxivID(f *testing.F) {
+	for _, seed := range []string{
+		"1234.5678v1",
+		"2301.00001",
+		"quant-ph/0301001",
+		"math.GT/0309136v2",
+		"http://arxiv.org/abs/1234.5678v1",
+		"https://www.arxiv.org/pdf/1234.5678.pdf?download=1",
+		"://",
+		"arxiv.org",
+		"arXiv:",
+		"",
+	} {
+		f.Add(seed)
+	}
+
+	forms := []string{
+		"%s",
+		"arXiv:%s",
+		"http://arxiv.org/abs/%s",
+		"https://arxiv.org/abs/%s/",
+		"https://www.arxiv.org/abs/%s?context=cs",
+		"http://export.arxiv.org/abs/%s#comments",
+		"https://arxiv.org/pdf/%s",
+		"https://arxiv.org/pdf/%s.pdf",
+	}
+
+	f.Fuzz(func(t *testing.T, input string) {
+		// Must never panic on arbitrary input
+		extractArxivID(input)
+
+		if !validArxivIDPattern.MatchString(input) {
+			return
+		}
+		for _, form := range forms {
+			got := extractArxivID(fmt.Sprintf(form, input))
+			if got != input {
+				t.Fatalf("extractArxivID(%q): expected %q, got %q", fmt.Sprintf(form, input), input, got)
+			}
+			if got == "" || !strings.ContainsAny(got, "./") {
+				t.Fatalf("extractArxivID(%q) returned malformed ID %q", fmt.Sprintf(form, input), got)
+			}
+		}
+	})
+}
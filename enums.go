@@ -210,3 +210,39 @@ const (
 	SortOrderAscending  SortOrder = "ascending"
 	SortOrderDescending SortOrder = "descending"
 )
+
+// QueryField represents a searchable field, written as its prefix in search queries
+type QueryField string
+
+const (
+	FieldTitle    QueryField = "ti"
+	FieldAbstract QueryField = "abs"
+	FieldAuthor   QueryField = "au"
+	FieldComment  QueryField = "co"
+	FieldAll      QueryField = "all"
+)
+
+// IsValid reports whether f is one of the defined query fields
+func (f QueryField) IsValid() bool {
+	switch f {
+	case FieldTitle, FieldAbstract, FieldAuthor, FieldComment, FieldAll:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsValid reports whether s is one of the sort criteria supported by arXiv
+func (s SortCriterion) IsValid() bool {
+	switch s {
+	case SortByRelevance, SortByLastUpdatedDate, SortBySubmittedDate:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsValid reports whether o is one of the sort orders supported by arXiv
+func (o SortOrder) IsValid() bool {
+	return o == SortOrderAscending || o == SortOrderDescending
+}
@@ -64,8 +64,8 @@ func main() {
 		Category(arxiv.CategoryCSMA).
 		Iterator(ctx)
 	// Collect first 5 papers
-	fmt.Println("First 5 papers using TakeSeq:")
-	first5 := arxiv.CollectSeq(arxiv.TakeSeq(iter.All(), 5))
+	fmt.Println("First 5 papers using Take:")
+	first5 := arxiv.CollectSeq(iter.Take(5))
 	for i, paper := range first5 {
 		fmt.Printf("%d. %s\n", i+1, paper.Title)
 	}
@@ -73,7 +73,7 @@ func main() {
 	// Reset and filter papers
 	iter.Reset()
 	fmt.Println("\nFiltered papers (containing 'debate' in title):")
-	filtered := arxiv.CollectSeq(arxiv.FilterSeq(iter.All(), func(paper *arxiv.Paper) bool {
+	filtered := arxiv.CollectSeq(iter.Filter(func(paper *arxiv.Paper) bool {
 		return strings.Contains(strings.ToLower(paper.Title), "debate")
 	}))
 	fmt.Println("total fetched:", iter.TotalFetched(), "total count:", iter.TotalCount())
@@ -124,16 +124,14 @@ func main() {
 
 	// Chain: Filter by recent years -> Take first 3 -> Process each
 	recentPapers := arxiv.TakeSeq(
-		arxiv.FilterSeq(iter.All(), func(paper *arxiv.Paper) bool {
+		iter.Filter(func(paper *arxiv.Paper) bool {
 			return paper.PublishedAt.Year() >= 2020
 		}),
 		3,
 	)
 
-	count := 0
-	for paper := range recentPapers {
-		count++
-		fmt.Printf("%d. %s\n", count, truncateTitle(paper.Title, 60))
+	for i, paper := range arxiv.EnumerateSeq(recentPapers) {
+		fmt.Printf("%d. %s\n", i+1, truncateTitle(paper.Title, 60))
 		fmt.Printf("   Year: %d, Authors: %d\n",
 			paper.PublishedAt.Year(),
 			len(paper.Authors))
@@ -175,10 +173,8 @@ func main() {
 		MaxResults(50). // Per request: 50 papers (efficient)
 		Iterator(ctx)
 
-	count = 0
-	for paper := range iter.All() {
-		count++
-		fmt.Printf("%d. %s\n", count, truncateTitle(paper.Title, 60))
+	for i, paper := range iter.Enumerate() {
+		fmt.Printf("%d. %s\n", i+1, truncateTitle(paper.Title, 60))
 	}
 	fmt.Printf("Total fetched: %d papers\n\n", iter.TotalFetched())
 
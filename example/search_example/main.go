@@ -59,7 +59,7 @@ func main() {
 	// Example 3: Using category constants for type safety
 	fmt.Println("\n\n=== Search by Category (Type-safe) ===")
 	categoryQuery := &arxiv.Query{
-		SearchQuery: fmt.Sprintf("cat:%s", arxiv.CategoryCSAI), // cs.AI category
+		SearchQuery: arxiv.CategoryFilter(arxiv.CategoryCSAI), // cs.AI category
 		MaxResults:  2,
 		SortBy:      string(arxiv.SortByRelevance),
 		SortOrder:   string(arxiv.SortOrderDescending),
@@ -92,7 +92,21 @@ func main() {
 		fmt.Printf("Found paper: %s\n", paper.Title)
 	}
 
-	// Example 5: Demonstrate all available sort criteria and orders
+	// Example 5: Daily digest of new papers across several categories
+	fmt.Println("\n\n=== Daily Digest ===")
+	digest, err := client.Digest(context.Background(),
+		[]arxiv.Category{arxiv.CategoryCSLG, arxiv.CategoryStatML},
+		time.Now().Add(-24*time.Hour), 5)
+	if err != nil {
+		log.Printf("Digest failed: %v", err)
+	} else {
+		fmt.Printf("%d new papers in the last day:\n", len(digest))
+		for i, paper := range digest {
+			fmt.Printf("%d. [%s] %s\n", i+1, paper.PrimaryCategory(), paper.Title)
+		}
+	}
+
+	// Example 6: Demonstrate all available sort criteria and orders
 	fmt.Println("\n\n=== Available Constants ===")
 	fmt.Printf("Sort Criteria:\n")
 	fmt.Printf("- Relevance: %s\n", arxiv.SortByRelevance)
@@ -109,7 +123,7 @@ func main() {
 	fmt.Printf("- Economics: %s\n", arxiv.CategoryEconEM)
 	fmt.Printf("- Machine Learning: %s\n", arxiv.CategoryCSLG)
 
-	// Example 6: Backward compatibility - original methods still work
+	// Example 7: Backward compatibility - original methods still work
 	fmt.Println("\n\n=== Backward Compatibility ===")
 	legacyClient := arxiv.NewClient() // Uses default options
 	legacyQuery := &arxiv.Query{
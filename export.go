@@ -0,0 +1,472 @@
+package arxiv
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"html"
+	"html/template"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Default number of abstract characters kept by Paper.Markdown
+const defaultMarkdownAbstractLength = 300
+
+// MarkdownOptions controls how Paper.MarkdownWithOptions renders a paper
+type MarkdownOptions struct {
+	// AbstractLength is the maximum number of characters of the abstract to include.
+	// Zero uses the default of 300; a negative value includes the full abstract.
+	AbstractLength int
+}
+
+// AbstractHTMLOptions controls how Paper.AbstractHTMLWithOptions renders the abstract
+type AbstractHTMLOptions struct {
+	// MathJax wraps $...$ and $$...$$ in spans with class "math" as \(...\) and \[...\],
+	// the delimiters MathJax and KaTeX look for by default
+	MathJax bool
+}
+
+var (
+	// Blank lines between the paragraphs of an abstract
+	paragraphBreakPattern = regexp.MustCompile(`\n[ \t]*\n\s*`)
+
+	// Display math ($$...$$) or inline math ($...$) not starting at an escaped \$
+	mathPattern = regexp.MustCompile(`(^|[^\\])(\$\$[^$]+\$\$|\$[^$]+\$)`)
+)
+
+// XML structures for serializing results back into an Atom feed
+type atomFeedOut struct {
+	XMLName         xml.Name       `xml:"feed"`
+	Xmlns           string         `xml:"xmlns,attr"`
+	XmlnsOpenSearch string         `xml:"xmlns:opensearch,attr"`
+	XmlnsArxiv      string         `xml:"xmlns:arxiv,attr"`
+	Title           string         `xml:"title"`
+	ID              string         `xml:"id"`
+	Updated         string         `xml:"updated"`
+	TotalResults    int            `xml:"opensearch:totalResults"`
+	StartIndex      int            `xml:"opensearch:startIndex"`
+	ItemsPerPage    int            `xml:"opensearch:itemsPerPage"`
+	Entries         []atomEntryOut `xml:"entry"`
+}
+
+type atomEntryOut struct {
+	ID         string            `xml:"id"`
+	Updated    string            `xml:"updated"`
+	Published  string            `xml:"published"`
+	Title      string            `xml:"title"`
+	Summary    string            `xml:"summary"`
+	Authors    []atomAuthorOut   `xml:"author"`
+	DOI        string            `xml:"arxiv:doi,omitempty"`
+	Comment    string            `xml:"arxiv:comment,omitempty"`
+	JournalRef string            `xml:"arxiv:journal_ref,omitempty"`
+	ACMClass   string            `xml:"arxiv:ACM-class,omitempty"`
+	MSCClass   string            `xml:"arxiv:MSC-class,omitempty"`
+	Links      []atomLinkOut     `xml:"link"`
+	Categories []atomCategoryOut `xml:"category"`
+}
+
+type atomAuthorOut struct {
+	Name string `xml:"name"`
+}
+
+type atomLinkOut struct {
+	Href  string `xml:"href,attr"`
+	Rel   string `xml:"rel,attr,omitempty"`
+	Type  string `xml:"type,attr,omitempty"`
+	Title string `xml:"title,attr,omitempty"`
+}
+
+type atomCategoryOut struct {
+	Term   string `xml:"term,attr"`
+	Scheme string `xml:"scheme,attr"`
+}
+
+// XML structures for serializing results into an RSS 2.0 feed
+type rssOut struct {
+	XMLName xml.Name      `xml:"rss"`
+	Version string        `xml:"version,attr"`
+	Channel rssChannelOut `xml:"channel"`
+}
+
+type rssChannelOut struct {
+	Title       string       `xml:"title"`
+	Link        string       `xml:"link"`
+	Description string       `xml:"description"`
+	Items       []rssItemOut `xml:"item"`
+}
+
+type rssItemOut struct {
+	Title       string     `xml:"title"`
+	Link        string     `xml:"link"`
+	Description string     `xml:"description"`
+	PubDate     string     `xml:"pubDate"`
+	GUID        rssGUIDOut `xml:"guid"`
+}
+
+type rssGUIDOut struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+// ToAtom serializes the results into an Atom feed using the same namespaces as the arXiv API,
+// so the output can be parsed again by this package
+func (r *SearchResults) ToAtom() ([]byte, error) {
+	feed := atomFeedOut{
+		Xmlns:           atomNamespace,
+		XmlnsOpenSearch: openSearchNamespace,
+		XmlnsArxiv:      arxivNamespace,
+		Title:           "arXiv Query Results",
+		ID:              "urn:arxiv-go:results",
+		TotalResults:    r.TotalCount,
+		StartIndex:      r.StartIndex,
+		ItemsPerPage:    r.ItemsPerPage,
+		Entries:         make([]atomEntryOut, len(r.Papers)),
+	}
+
+	// The feed is as fresh as its most recently updated paper
+	var updated time.Time
+	for i, paper := range r.Papers {
+		if paper.UpdatedAt.After(updated) {
+			updated = paper.UpdatedAt
+		}
+		feed.Entries[i] = paperToAtomEntry(paper)
+	}
+	feed.Updated = updated.Format(time.RFC3339)
+
+	data, err := xml.MarshalIndent(feed, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal Atom feed: %w", err)
+	}
+	return append([]byte(xml.Header), data...), nil
+}
+
+// paperToAtomEntry converts a Paper to an Atom entry, the inverse of convertEntryToPaper
+func paperToAtomEntry(paper Paper) atomEntryOut {
+	authors := make([]atomAuthorOut, len(paper.Authors))
+	for i, author := range paper.Authors {
+		authors[i] = atomAuthorOut{Name: author.Name}
+	}
+
+	links := make([]atomLinkOut, len(paper.Links))
+	for i, link := range paper.Links {
+		links[i] = atomLinkOut{
+			Href:  link.Href,
+			Rel:   link.Rel,
+			Type:  link.Type,
+			Title: link.Title,
+		}
+	}
+
+	categories := make([]atomCategoryOut, len(paper.Categories))
+	for i, cat := range paper.Categories {
+		categories[i] = atomCategoryOut{Term: cat, Scheme: arxivNamespace}
+	}
+
+	return atomEntryOut{
+		ID:         paper.absURL(),
+		Updated:    paper.UpdatedAt.Format(time.RFC3339),
+		Published:  paper.PublishedAt.Format(time.RFC3339),
+		Title:      paper.Title,
+		Summary:    paper.Abstract,
+		Authors:    authors,
+		DOI:        paper.DOI,
+		Comment:    paper.Comment,
+		JournalRef: paper.JournalRef,
+		ACMClass:   paper.ACMClass,
+		MSCClass:   paper.MSCClass,
+		Links:      links,
+		Categories: categories,
+	}
+}
+
+// ToRSS serializes the results into an RSS 2.0 feed with one item per paper,
+// using the abstract as the description and the arXiv ID as the guid
+func (r *SearchResults) ToRSS(channelTitle, channelLink string) ([]byte, error) {
+	feed := rssOut{
+		Version: "2.0",
+		Channel: rssChannelOut{
+			Title:       channelTitle,
+			Link:        channelLink,
+			Description: channelTitle,
+			Items:       make([]rssItemOut, len(r.Papers)),
+		},
+	}
+
+	for i, paper := range r.Papers {
+		feed.Channel.Items[i] = rssItemOut{
+			Title:       paper.Title,
+			Link:        paper.absURL(),
+			Description: paper.Abstract,
+			PubDate:     paper.PublishedAt.Format(time.RFC1123Z),
+			GUID:        rssGUIDOut{IsPermaLink: false, Value: paper.ID},
+		}
+	}
+
+	data, err := xml.MarshalIndent(feed, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal RSS feed: %w", err)
+	}
+	return append([]byte(xml.Header), data...), nil
+}
+
+// csvHeader is the header row written by SearchResults.WriteCSV
+var csvHeader = []string{"id", "title", "authors", "primary_category", "published_at", "updated_at", "doi", "abs_url"}
+
+// WriteCSV writes the results to w as CSV: a header row followed by one row per paper.
+// Authors are joined with semicolons and dates are formatted as RFC 3339.
+func (r *SearchResults) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvHeader); err != nil {
+		return fmt.Errorf("failed to write CSV header: %w", err)
+	}
+
+	for i := range r.Papers {
+		paper := &r.Papers[i]
+		authors := make([]string, len(paper.Authors))
+		for j, author := range paper.Authors {
+			authors[j] = author.Name
+		}
+
+		record := []string{
+			paper.ID,
+			paper.Title,
+			strings.Join(authors, "; "),
+			paper.PrimaryCategory(),
+			paper.PublishedAt.Format(time.RFC3339),
+			paper.UpdatedAt.Format(time.RFC3339),
+			paper.DOI,
+			paper.absURL(),
+		}
+		if err := cw.Write(record); err != nil {
+			return fmt.Errorf("failed to write CSV row for %s: %w", paper.ID, err)
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteNDJSON writes each remaining paper to w as one JSON object per line and returns
+// the number of papers written. Each line is encoded in full before it is written, so
+// output stopped by an error always ends at a line boundary. If w has a Flush method
+// (e.g. *bufio.Writer), it is flushed after every line.
+func (it *Iterator) WriteNDJSON(w io.Writer) (int, error) {
+	flusher, _ := w.(interface{ Flush() error })
+
+	count := 0
+	for paper, err := range it.AllWithError() {
+		if err != nil {
+			return count, err
+		}
+
+		line, err := json.Marshal(paper)
+		if err != nil {
+			return count, fmt.Errorf("failed to encode paper %s: %w", paper.ID, err)
+		}
+		if _, err := w.Write(append(line, '\n')); err != nil {
+			return count, err
+		}
+		if flusher != nil {
+			if err := flusher.Flush(); err != nil {
+				return count, err
+			}
+		}
+		count++
+	}
+	return count, nil
+}
+
+// WriteBibTeX writes each remaining paper to w as a BibTeX entry, separated by blank lines,
+// and returns the number of entries written. Keys come from CitationKey, suffixed as by
+// CitationKeys when they repeat. Each entry is rendered in full before it is written, so
+// output stopped by an error holds only complete entries. If w has a Flush method (e.g.
+// *bufio.Writer), it is flushed after every entry.
+func (it *Iterator) WriteBibTeX(w io.Writer) (int, error) {
+	flusher, _ := w.(interface{ Flush() error })
+
+	count := 0
+	used := make(map[string]bool)
+	for paper, err := range it.AllWithError() {
+		if err != nil {
+			return count, err
+		}
+
+		entry := paper.bibTeX(uniqueCitationKey(paper.CitationKey(), used))
+		if count > 0 {
+			entry = "\n" + entry
+		}
+		if _, err := io.WriteString(w, entry); err != nil {
+			return count, err
+		}
+		if flusher != nil {
+			if err := flusher.Flush(); err != nil {
+				return count, err
+			}
+		}
+		count++
+	}
+	return count, nil
+}
+
+// BibTeX renders the paper as a BibTeX @misc entry keyed by CitationKey, with the eprint
+// fields arXiv's own export uses. Titles keep their LaTeX markup.
+func (p *Paper) BibTeX() string {
+	return p.bibTeX(p.CitationKey())
+}
+
+// bibTeX renders the paper as a BibTeX entry with the given key
+func (p *Paper) bibTeX(key string) string {
+	authors := make([]string, len(p.Authors))
+	for i, author := range p.Authors {
+		if given, family := author.ParseName(); given != "" {
+			authors[i] = family + ", " + given
+		} else {
+			authors[i] = family
+		}
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "@misc{%s,\n", key)
+	field := func(name, value string) {
+		if value = bibTeXValue(value); value != "" {
+			fmt.Fprintf(&b, "  %s = {%s},\n", name, value)
+		}
+	}
+	field("title", p.Title)
+	field("author", strings.Join(authors, " and "))
+	if !p.PublishedAt.IsZero() {
+		field("year", strconv.Itoa(p.PublishedAt.Year()))
+	}
+	field("eprint", p.Key())
+	field("archivePrefix", "arXiv")
+	field("primaryClass", p.PrimaryCategory())
+	field("doi", p.DOI)
+	field("url", absURLPrefix+p.Key())
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// bibTeXValue collapses whitespace in value and drops its braces unless they are balanced,
+// since an unbalanced brace would end the field early or swallow the rest of the file
+func bibTeXValue(value string) string {
+	value = strings.Join(strings.Fields(value), " ")
+	depth := 0
+	for _, r := range value {
+		switch r {
+		case '{':
+			depth++
+		case '}':
+			depth--
+		}
+		if depth < 0 {
+			break
+		}
+	}
+	if depth != 0 {
+		value = strings.NewReplacer("{", "", "}", "").Replace(value)
+	}
+	return value
+}
+
+// Markdown renders the paper as a Markdown snippet using the default options
+func (p *Paper) Markdown() string {
+	return p.MarkdownWithOptions(MarkdownOptions{})
+}
+
+// MarkdownWithOptions renders the paper as a Markdown snippet: bold title, link to the
+// abstract page, authors, truncated abstract, categories as inline code and published date
+func (p *Paper) MarkdownWithOptions(opts MarkdownOptions) string {
+	length := opts.AbstractLength
+	if length == 0 {
+		length = defaultMarkdownAbstractLength
+	}
+
+	names := make([]string, len(p.Authors))
+	for i, author := range p.Authors {
+		names[i] = author.Name
+	}
+
+	categories := make([]string, len(p.Categories))
+	for i, cat := range p.Categories {
+		categories[i] = "`" + cat + "`"
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "**%s**\n", p.Title)
+	fmt.Fprintf(&b, "[arXiv:%s](%s)\n", p.ID, p.absURL())
+	if len(names) > 0 {
+		fmt.Fprintf(&b, "*%s*\n", strings.Join(names, ", "))
+	}
+	if p.Abstract != "" {
+		fmt.Fprintf(&b, "> %s\n", truncateText(p.Abstract, length))
+	}
+	if len(categories) > 0 {
+		fmt.Fprintf(&b, "Categories: %s\n", strings.Join(categories, " "))
+	}
+	fmt.Fprintf(&b, "Published: %s\n", p.PublishedAt.Format("2006-01-02"))
+	return b.String()
+}
+
+// AbstractHTML renders the abstract as HTML-escaped paragraphs using the default options
+func (p *Paper) AbstractHTML() template.HTML {
+	return p.AbstractHTMLWithOptions(AbstractHTMLOptions{})
+}
+
+// AbstractHTMLWithOptions renders the abstract as HTML for web pages: the text is escaped
+// and each paragraph, separated by a blank line, is wrapped in <p>. Other line breaks
+// become spaces. Clients without PreserveWhitespace set, the default, have already joined
+// the paragraphs into one. LaTeX markup is left as is apart from the optional math spans.
+func (p *Paper) AbstractHTMLWithOptions(opts AbstractHTMLOptions) template.HTML {
+	var b strings.Builder
+	for _, paragraph := range paragraphBreakPattern.Split(strings.TrimSpace(p.Abstract), -1) {
+		paragraph = strings.Join(strings.Fields(paragraph), " ")
+		if paragraph == "" {
+			continue
+		}
+		b.WriteString("<p>")
+		if opts.MathJax {
+			writeMathHTML(&b, paragraph)
+		} else {
+			b.WriteString(html.EscapeString(paragraph))
+		}
+		b.WriteString("</p>\n")
+	}
+	return template.HTML(b.String())
+}
+
+// writeMathHTML writes text escaped, with its $...$ and $$...$$ math wrapped in spans
+func writeMathHTML(b *strings.Builder, text string) {
+	last := 0
+	for _, m := range mathPattern.FindAllStringSubmatchIndex(text, -1) {
+		// m[4]:m[5] is the math including its dollar signs
+		b.WriteString(html.EscapeString(text[last:m[4]]))
+		math := text[m[4]:m[5]]
+		if strings.HasPrefix(math, "$$") {
+			b.WriteString(`<span class="math display">\[` + html.EscapeString(math[2:len(math)-2]) + `\]</span>`)
+		} else {
+			b.WriteString(`<span class="math inline">\(` + html.EscapeString(math[1:len(math)-1]) + `\)</span>`)
+		}
+		last = m[5]
+	}
+	b.WriteString(html.EscapeString(text[last:]))
+}
+
+// truncateText shortens text to at most n characters, marking the cut with an ellipsis.
+// A negative n leaves text unchanged.
+func truncateText(text string, n int) string {
+	runes := []rune(text)
+	if n < 0 || len(runes) <= n {
+		return text
+	}
+	return strings.TrimSpace(string(runes[:n])) + "…"
+}
+
+// absURL returns the paper's arXiv abstract page URL
+func (p *Paper) absURL() string {
+	return absURLPrefix + p.ID
+}
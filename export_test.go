@@ -0,0 +1,350 @@
+package arxiv
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSearchResults_ToAtom(t *testing.T) {
+	client := NewClient()
+	original, err := client.parseSearchResponse([]byte(mockXMLResponse), false)
+	if err != nil {
+		t.Fatalf("Failed to parse mock response: %v", err)
+	}
+
+	data, err := original.ToAtom()
+	if err != nil {
+		t.Fatalf("ToAtom failed: %v", err)
+	}
+
+	for _, want := range []string{
+		`xmlns="http://www.w3.org/2005/Atom"`,
+		`<opensearch:totalResults>50000</opensearch:totalResults>`,
+		`<id>http://arxiv.org/abs/1234.5678v1</id>`,
+		`<arxiv:doi>10.1234/test.doi</arxiv:doi>`,
+	} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("Expected Atom output to contain %s", want)
+		}
+	}
+
+	// The generated feed must parse back into the same results
+	roundTrip, err := client.parseSearchResponse(data, false)
+	if err != nil {
+		t.Fatalf("Failed to parse generated Atom feed: %v", err)
+	}
+
+	if roundTrip.TotalCount != original.TotalCount {
+		t.Errorf("Expected TotalCount %d, got %d", original.TotalCount, roundTrip.TotalCount)
+	}
+
+	if len(roundTrip.Papers) != 1 {
+		t.Fatalf("Expected 1 paper after round trip, got %d", len(roundTrip.Papers))
+	}
+
+	got, want := roundTrip.Papers[0], original.Papers[0]
+	if !got.PublishedAt.Equal(want.PublishedAt) || !got.UpdatedAt.Equal(want.UpdatedAt) {
+		t.Errorf("Expected dates %v/%v, got %v/%v", want.PublishedAt, want.UpdatedAt, got.PublishedAt, got.UpdatedAt)
+	}
+	got.PublishedAt, got.UpdatedAt = want.PublishedAt, want.UpdatedAt
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Round-tripped paper differs:\n got %+v\nwant %+v", got, want)
+	}
+}
+
+func TestSearchResults_ToRSS(t *testing.T) {
+	client := NewClient()
+	results, err := client.parseSearchResponse([]byte(mockXMLResponse), false)
+	if err != nil {
+		t.Fatalf("Failed to parse mock response: %v", err)
+	}
+
+	data, err := results.ToRSS("Quantum feed", "https://example.com/quantum")
+	if err != nil {
+		t.Fatalf("ToRSS failed: %v", err)
+	}
+
+	var feed struct {
+		Version string `xml:"version,attr"`
+		Channel struct {
+			Title string `xml:"title"`
+			Link  string `xml:"link"`
+			Items []struct {
+				Title       string `xml:"title"`
+				Link        string `xml:"link"`
+				Description string `xml:"description"`
+				PubDate     string `xml:"pubDate"`
+				GUID        string `xml:"guid"`
+			} `xml:"item"`
+		} `xml:"channel"`
+	}
+	if err := xml.Unmarshal(data, &feed); err != nil {
+		t.Fatalf("Failed to parse generated RSS: %v", err)
+	}
+
+	if feed.Version != "2.0" {
+		t.Errorf("Expected RSS version 2.0, got '%s'", feed.Version)
+	}
+	if feed.Channel.Title != "Quantum feed" || feed.Channel.Link != "https://example.com/quantum" {
+		t.Errorf("Unexpected channel title/link: '%s' '%s'", feed.Channel.Title, feed.Channel.Link)
+	}
+	if len(feed.Channel.Items) != 1 {
+		t.Fatalf("Expected 1 item, got %d", len(feed.Channel.Items))
+	}
+
+	item := feed.Channel.Items[0]
+	if item.Title != "Test Paper on Quantum Computing" {
+		t.Errorf("Unexpected item title '%s'", item.Title)
+	}
+	if item.Link != "http://arxiv.org/abs/1234.5678v1" {
+		t.Errorf("Unexpected item link '%s'", item.Link)
+	}
+	if item.Description != results.Papers[0].Abstract {
+		t.Errorf("Expected abstract as description, got '%s'", item.Description)
+	}
+	if item.PubDate != "Sun, 01 Jan 2023 00:00:00 -0500" {
+		t.Errorf("Expected RFC1123Z pubDate, got '%s'", item.PubDate)
+	}
+	if item.GUID != "1234.5678v1" {
+		t.Errorf("Expected guid '1234.5678v1', got '%s'", item.GUID)
+	}
+}
+
+func TestPaper_Markdown(t *testing.T) {
+	client := NewClient()
+	results, err := client.parseSearchResponse([]byte(mockXMLResponse), false)
+	if err != nil {
+		t.Fatalf("Failed to parse mock response: %v", err)
+	}
+	paper := results.Papers[0]
+
+	expected := "**Test Paper on Quantum Computing**\n" +
+		"[arXiv:1234.5678v1](http://arxiv.org/abs/1234.5678v1)\n" +
+		"*John Doe, Jane Smith*\n" +
+		"> This is a test abstract for quantum computing research.\n" +
+		"Categories: `quant-ph` `cs.ET`\n" +
+		"Published: 2023-01-01\n"
+	if got := paper.Markdown(); got != expected {
+		t.Errorf("Unexpected Markdown:\n got %q\nwant %q", got, expected)
+	}
+
+	short := paper.MarkdownWithOptions(MarkdownOptions{AbstractLength: 14})
+	if !strings.Contains(short, "> This is a test…\n") {
+		t.Errorf("Expected abstract truncated to 14 characters, got %q", short)
+	}
+
+	full := paper.MarkdownWithOptions(MarkdownOptions{AbstractLength: -1})
+	if !strings.Contains(full, paper.Abstract) {
+		t.Errorf("Expected full abstract with negative length, got %q", full)
+	}
+}
+
+func TestPaper_AbstractHTML(t *testing.T) {
+	paper := &Paper{Abstract: "  We show that a < b & c > d\n  for all <script>x</script>.\n  \n  A second paragraph\nwith $O(n<m)$ cost.\n"}
+
+	expected := "<p>We show that a &lt; b &amp; c &gt; d for all &lt;script&gt;x&lt;/script&gt;.</p>\n" +
+		"<p>A second paragraph with $O(n&lt;m)$ cost.</p>\n"
+	if got := string(paper.AbstractHTML()); got != expected {
+		t.Errorf("Expected %q, got %q", expected, got)
+	}
+
+	math := &Paper{Abstract: `Costs $x<y$ and $$\sum_i a_i$$, not \$5.`}
+	expected = `<p>Costs <span class="math inline">\(x&lt;y\)</span> and ` +
+		`<span class="math display">\[\sum_i a_i\]</span>, not \$5.</p>` + "\n"
+	if got := string(math.AbstractHTMLWithOptions(AbstractHTMLOptions{MathJax: true})); got != expected {
+		t.Errorf("Expected %q, got %q", expected, got)
+	}
+
+	if got := (&Paper{}).AbstractHTML(); got != "" {
+		t.Errorf("Expected no HTML for an empty abstract, got %q", got)
+	}
+}
+
+func TestIterator_WriteNDJSON(t *testing.T) {
+	server := newPagingServer(5)
+	defer server.Close()
+
+	client := newFastClient(server.URL)
+	it := client.NewQuery().SearchQuery("test").MaxResults(2).Iterator(context.Background())
+
+	var buf bytes.Buffer
+	n, err := it.WriteNDJSON(&buf)
+	if err != nil {
+		t.Fatalf("WriteNDJSON failed: %v", err)
+	}
+	if n != 5 {
+		t.Errorf("Expected 5 papers written, got %d", n)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 5 {
+		t.Fatalf("Expected 5 lines, got %d", len(lines))
+	}
+	for i, line := range lines {
+		var paper Paper
+		if err := json.Unmarshal([]byte(line), &paper); err != nil {
+			t.Fatalf("Line %d is not valid JSON: %v", i, err)
+		}
+		if expected := fmt.Sprintf("2301.%05dv1", i); paper.ID != expected {
+			t.Errorf("Line %d: expected ID '%s', got '%s'", i, expected, paper.ID)
+		}
+	}
+}
+
+func TestIterator_WriteNDJSONError(t *testing.T) {
+	// First page succeeds, the second fails
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if start := r.URL.Query().Get("start"); start != "" && start != "0" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/xml")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(generateFeed(4, 0, 2)))
+	}))
+	defer server.Close()
+
+	client := NewClientWithOptions(ClientOptions{RateLimit: time.Nanosecond, RetryAttempts: 1})
+	client.baseURL = server.URL
+	it := client.NewQuery().SearchQuery("test").MaxResults(2).Iterator(context.Background())
+
+	var buf bytes.Buffer
+	n, err := it.WriteNDJSON(&buf)
+	if err == nil {
+		t.Fatal("Expected error from failing second page")
+	}
+	if n != 2 {
+		t.Errorf("Expected 2 papers written before the error, got %d", n)
+	}
+
+	output := buf.String()
+	if !strings.HasSuffix(output, "\n") || strings.Count(output, "\n") != 2 {
+		t.Errorf("Expected output to end after 2 complete lines, got %q", output)
+	}
+}
+
+func TestIterator_WriteBibTeX(t *testing.T) {
+	server := newPagingServer(5)
+	defer server.Close()
+
+	client := newFastClient(server.URL)
+	it := client.NewQuery().SearchQuery("test").MaxResults(2).Iterator(context.Background())
+
+	var buf bytes.Buffer
+	n, err := it.WriteBibTeX(&buf)
+	if err != nil {
+		t.Fatalf("WriteBibTeX failed: %v", err)
+	}
+	if n != 5 {
+		t.Errorf("Expected 5 entries written, got %d", n)
+	}
+
+	output := buf.String()
+	entries := strings.Split(output, "\n\n")
+	if len(entries) != 5 || strings.Count(output, "@misc{") != 5 {
+		t.Fatalf("Expected 5 entries separated by blank lines, got %q", output)
+	}
+	for i, entry := range entries {
+		if !strings.HasPrefix(entry, "@misc{") || !strings.HasSuffix(strings.TrimSpace(entry), "}") {
+			t.Errorf("Expected entry %d to be complete, got %q", i, entry)
+		}
+		if eprint := fmt.Sprintf("eprint = {2301.%05d}", i); !strings.Contains(entry, eprint) {
+			t.Errorf("Expected entry %d to contain %q, got %q", i, eprint, entry)
+		}
+	}
+}
+
+func TestPaper_BibTeX(t *testing.T) {
+	paper := &Paper{
+		ID:          "2301.00001v2",
+		Title:       "Deep {Learning}\n  for {Physics",
+		Authors:     []Author{{Name: "Yann LeCun"}, {Name: "Johannes van der Waals"}, {Name: "Plato"}},
+		Categories:  []string{"cs.LG", "physics.comp-ph"},
+		PublishedAt: time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC),
+		DOI:         "10.1234/example",
+	}
+
+	expected := `@misc{lecun2023deep,
+  title = {Deep Learning for Physics},
+  author = {LeCun, Yann and van der Waals, Johannes and Plato},
+  year = {2023},
+  eprint = {2301.00001},
+  archivePrefix = {arXiv},
+  primaryClass = {cs.LG},
+  doi = {10.1234/example},
+  url = {http://arxiv.org/abs/2301.00001},
+}
+`
+	if got := paper.BibTeX(); got != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, got)
+	}
+
+	// Balanced braces are kept
+	paper.Title = "The {BERT} Model"
+	if got := paper.BibTeX(); !strings.Contains(got, "title = {The {BERT} Model},") {
+		t.Errorf("Expected balanced braces to be kept, got %q", got)
+	}
+}
+
+func TestSearchResults_WriteCSV(t *testing.T) {
+	published := time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)
+	results := &SearchResults{
+		Papers: []Paper{
+			{
+				ID:          "2301.00001v1",
+				Title:       `Attention, "Transformers", and More`,
+				Authors:     []Author{{Name: "Alice Smith"}, {Name: "Bob Jones"}},
+				Categories:  []string{"cs.LG", "stat.ML"},
+				PublishedAt: published,
+				UpdatedAt:   published,
+				DOI:         "10.1000/xyz",
+			},
+			{ID: "2301.00002v1", Title: "Multi-line\ntitle"},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := results.WriteCSV(&buf); err != nil {
+		t.Fatalf("WriteCSV failed: %v", err)
+	}
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("Output does not parse as CSV: %v", err)
+	}
+	if len(records) != 3 {
+		t.Fatalf("Expected header and 2 rows, got %d records", len(records))
+	}
+
+	if !reflect.DeepEqual(records[0], csvHeader) {
+		t.Errorf("Unexpected header: %v", records[0])
+	}
+
+	expected := []string{
+		"2301.00001v1",
+		`Attention, "Transformers", and More`,
+		"Alice Smith; Bob Jones",
+		"cs.LG",
+		"2023-01-02T03:04:05Z",
+		"2023-01-02T03:04:05Z",
+		"10.1000/xyz",
+		"http://arxiv.org/abs/2301.00001v1",
+	}
+	if !reflect.DeepEqual(records[1], expected) {
+		t.Errorf("Expected row %v, got %v", expected, records[1])
+	}
+
+	if records[2][1] != "Multi-line\ntitle" || records[2][3] != "" {
+		t.Errorf("Unexpected second row: %v", records[2])
+	}
+}
@@ -1,3 +1,10 @@
 module github.com/furudenipa/arxiv-go
 
 go 1.24.2
+
+require (
+	golang.org/x/net v0.50.0
+	golang.org/x/sync v0.19.0
+)
+
+require golang.org/x/text v0.34.0 // indirect
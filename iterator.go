@@ -2,9 +2,17 @@ package arxiv
 
 import (
 	"context"
+	"fmt"
 	"iter"
+	"sync"
 )
 
+// defaultIDBatchSize is how many IDs the iterator sends per id_list request
+const defaultIDBatchSize = 100
+
+// maxCapacityHint bounds slice preallocation so a huge TotalCount can't trigger a giant allocation
+const maxCapacityHint = 10000
+
 // IteratorState represents the current state of the iterator
 type IteratorState int
 
@@ -102,6 +110,56 @@ func (a ConsumeAction) Apply(state State) State {
 	}
 }
 
+// SkipPageAction represents moving past a page that returned no papers
+// while more pages are still expected (e.g. an id_list batch of unknown IDs)
+type SkipPageAction struct {
+	Results *SearchResults
+}
+
+func (a SkipPageAction) Apply(state State) State {
+	return State{
+		Current:      StateReady,
+		CurrentPage:  state.CurrentPage + 1,
+		CurrentIndex: 0,
+		TotalFetched: state.TotalFetched,
+		Error:        nil,
+		Results:      a.Results,
+	}
+}
+
+// ExhaustAction represents reaching the end of the results (or the limit),
+// keeping the position so the iterator doesn't yield the current page again
+type ExhaustAction struct {
+	Consumed int // Papers yielded outside the current page, e.g. by CollectConcurrent
+}
+
+func (a ExhaustAction) Apply(state State) State {
+	return State{
+		Current:      StateExhausted,
+		CurrentPage:  state.CurrentPage,
+		CurrentIndex: state.CurrentIndex,
+		TotalFetched: state.TotalFetched + a.Consumed,
+		Error:        nil,
+		Results:      state.Results,
+	}
+}
+
+// ResumeAction represents restoring an iterator from a checkpoint; nothing is fetched yet
+type ResumeAction struct {
+	TotalFetched int
+}
+
+func (a ResumeAction) Apply(state State) State {
+	return State{
+		Current:      StateInitial,
+		CurrentPage:  0,
+		CurrentIndex: 0,
+		TotalFetched: a.TotalFetched,
+		Error:        nil,
+		Results:      nil,
+	}
+}
+
 // StateManager manages state transitions
 type StateManager struct {
 	state State
@@ -130,22 +188,57 @@ func (sm *StateManager) Reset() {
 	sm.state = State{Current: StateInitial}
 }
 
-// Paginator handles pagination logic
+// Paginator handles pagination logic.
+// Queries with an IDList are paged by sending the IDs in batches of IDBatchSize
+// instead of using start/max_results offsets.
 type Paginator struct {
-	query *Query
+	query       *Query
+	IDBatchSize int
+	startOffset int // Start index of the first page: Query.Start, or the checkpoint's when resuming
 }
 
 // NewPaginator creates a new paginator
 func NewPaginator(query *Query) *Paginator {
-	return &Paginator{query: query}
+	p := &Paginator{query: query, IDBatchSize: defaultIDBatchSize}
+	if query != nil {
+		p.startOffset = query.Start
+	}
+	return p
 }
 
-// CalculateStartIndex calculates the start index for the next page
+// pagesByID reports whether the query is paged through its IDList
+func (p *Paginator) pagesByID() bool {
+	return p.query != nil && len(p.query.IDList) > 0
+}
+
+// IDBatch returns the IDs to request for the given page (0-based)
+func (p *Paginator) IDBatch(page int) []string {
+	batchSize := p.IDBatchSize
+	if batchSize <= 0 {
+		batchSize = defaultIDBatchSize
+	}
+
+	start := min(page*batchSize, len(p.query.IDList))
+	end := min(start+batchSize, len(p.query.IDList))
+	return p.query.IDList[start:end]
+}
+
+// CalculateStartIndex calculates the start index of the page after results, or of the
+// first page when results is nil. It continues after the papers actually returned rather
+// than assuming full pages, since page sizes vary with arXiv's caps and the Limit.
+//
+// Deprecated: currentPage is ignored; the start index depends only on results.
 func (p *Paginator) CalculateStartIndex(currentPage int, results *SearchResults) int {
+	return p.nextStart(results)
+}
+
+// nextStart returns the start index of the page after results, or of the first page when
+// results is nil
+func (p *Paginator) nextStart(results *SearchResults) int {
 	if results != nil {
-		return results.StartIndex + len(results.Papers)
+		return results.StartIndex + results.pageLen()
 	}
-	return currentPage * p.query.MaxResults
+	return p.startOffset
 }
 
 // CalculateMaxResults calculates how many results to fetch considering the limit
@@ -162,6 +255,14 @@ func (p *Paginator) CalculateMaxResults(totalFetched int) int {
 
 // HasMoreData checks if more data might be available
 func (p *Paginator) HasMoreData(state State) bool {
+	// ID batches continue until every ID has been requested
+	if p.pagesByID() {
+		if p.query.Limit > 0 && state.TotalFetched >= p.query.Limit {
+			return false
+		}
+		return len(p.IDBatch(state.CurrentPage)) > 0
+	}
+
 	// If we haven't fetched anything yet, there might be data
 	if state.Results == nil {
 		return true
@@ -173,27 +274,37 @@ func (p *Paginator) HasMoreData(state State) bool {
 	}
 
 	// Check total count if known
-	expectedTotal := state.Results.StartIndex + len(state.Results.Papers)
+	expectedTotal := state.Results.StartIndex + state.Results.pageLen()
 	if state.Results.TotalCount > 0 && expectedTotal >= state.Results.TotalCount {
 		return false
 	}
 
-	// If we got fewer results than requested, probably no more
-	if len(state.Results.Papers) < p.query.MaxResults {
+	// If we got fewer results than a full page, probably no more
+	if pageLen := state.Results.pageLen(); pageLen == 0 || pageLen < p.pageSize(state.Results) {
 		return false
 	}
 
 	return true
 }
 
+// pageSize returns the number of papers in a full page. arXiv reports its page size
+// as itemsPerPage and may cap it below the requested MaxResults, in which case a
+// page of ItemsPerPage papers doesn't mean the results ran out.
+func (p *Paginator) pageSize(results *SearchResults) int {
+	if results.ItemsPerPage > 0 && results.ItemsPerPage < p.query.MaxResults {
+		return results.ItemsPerPage
+	}
+	return p.query.MaxResults
+}
+
 // Fetcher handles API requests
 type Fetcher struct {
-	client *Client
+	client Searcher
 	ctx    context.Context
 }
 
 // NewFetcher creates a new fetcher
-func NewFetcher(client *Client, ctx context.Context) *Fetcher {
+func NewFetcher(client Searcher, ctx context.Context) *Fetcher {
 	return &Fetcher{client: client, ctx: ctx}
 }
 
@@ -219,15 +330,104 @@ type Iterator struct {
 }
 
 // NewIterator creates a new iterator
-func NewIterator(client *Client, query *Query, ctx context.Context) *Iterator {
+func NewIterator(client Searcher, query *Query, ctx context.Context) *Iterator {
+	paginator := NewPaginator(query)
+	if c, ok := client.(*Client); ok {
+		paginator.IDBatchSize = c.idBatchSize()
+	}
+
 	return &Iterator{
-		paginator:    NewPaginator(query),
+		paginator:    paginator,
 		fetcher:      NewFetcher(client, ctx),
 		stateManager: NewStateManager(),
 		query:        query,
 	}
 }
 
+// IteratorCheckpoint is a serializable snapshot of an iterator's position.
+// Persist it (e.g. as JSON) and pass it to Client.ResumeIterator to continue
+// a harvest without re-fetching the papers already yielded.
+type IteratorCheckpoint struct {
+	// Query is the iterator's query. For ID list queries, IDList holds only the
+	// IDs that have not been reached yet.
+	Query Query `json:"query"`
+
+	// Start is the index of the next result to fetch (unused for ID list queries)
+	Start int `json:"start"`
+
+	// TotalFetched is the number of papers yielded so far, counted against Query.Limit
+	TotalFetched int `json:"total_fetched"`
+
+	// Exhausted reports that the iterator had already yielded everything
+	Exhausted bool `json:"exhausted,omitempty"`
+}
+
+// State returns a checkpoint of the iterator's current position
+func (it *Iterator) State() IteratorCheckpoint {
+	state := it.stateManager.GetState()
+	checkpoint := IteratorCheckpoint{
+		TotalFetched: state.TotalFetched,
+		Exhausted:    state.Current == StateExhausted,
+	}
+	if it.query == nil {
+		return checkpoint
+	}
+	checkpoint.Query = *it.query
+
+	switch {
+	case it.paginator.pagesByID():
+		checkpoint.Query.IDList = it.query.IDList[it.idListPosition(state):]
+		checkpoint.Exhausted = checkpoint.Exhausted || len(checkpoint.Query.IDList) == 0
+	case state.Results != nil && state.CurrentIndex >= len(state.Results.Papers):
+		checkpoint.Start = state.Results.StartIndex + state.Results.pageLen()
+	case state.Results != nil:
+		checkpoint.Start = state.Results.StartIndex + state.CurrentIndex
+	default:
+		checkpoint.Start = it.paginator.nextStart(nil)
+	}
+	return checkpoint
+}
+
+// idListPosition returns the index in the query's IDList of the first ID not yet yielded.
+// arXiv answers an id_list in request order, so the yielded papers of the current batch
+// always match a prefix of it (minus any unknown IDs).
+func (it *Iterator) idListPosition(state State) int {
+	batchSize := it.paginator.IDBatchSize
+	if batchSize <= 0 {
+		batchSize = defaultIDBatchSize
+	}
+	if state.Results == nil || state.Current != StateReady {
+		return min(state.CurrentPage*batchSize, len(it.query.IDList))
+	}
+
+	// The batch being consumed was fetched for the page before CurrentPage
+	page := state.CurrentPage - 1
+	position := page * batchSize
+	if state.CurrentIndex == 0 {
+		return position
+	}
+
+	last := BaseID(state.Results.Papers[state.CurrentIndex-1].ID)
+	for i, id := range it.paginator.IDBatch(page) {
+		if BaseID(id) == last {
+			return position + i + 1
+		}
+	}
+	return position + state.CurrentIndex
+}
+
+// ResumeIterator creates an iterator that continues from a checkpoint taken with Iterator.State
+func (c *Client) ResumeIterator(ctx context.Context, checkpoint IteratorCheckpoint) *Iterator {
+	query := checkpoint.Query
+	it := NewIterator(c, &query, ctx)
+	it.paginator.startOffset = checkpoint.Start
+	it.stateManager.Transition(ResumeAction{TotalFetched: checkpoint.TotalFetched})
+	if checkpoint.Exhausted {
+		it.stateManager.Transition(ExhaustAction{})
+	}
+	return it
+}
+
 // needsMoreData checks if we need to fetch more data
 func (it *Iterator) needsMoreData(state State) bool {
 	// No results yet
@@ -243,6 +443,36 @@ func (it *Iterator) needsMoreData(state State) bool {
 	return false
 }
 
+// nextPageQuery returns the query for the page after the one in state
+func (it *Iterator) nextPageQuery(state State) Query {
+	nextQuery := *it.query
+	if it.paginator.pagesByID() {
+		nextQuery.IDList = it.paginator.IDBatch(state.CurrentPage)
+		nextQuery.Start = 0
+		nextQuery.MaxResults = len(nextQuery.IDList)
+	} else {
+		nextQuery.Start = it.paginator.nextStart(state.Results)
+		nextQuery.MaxResults = it.paginator.CalculateMaxResults(state.TotalFetched)
+	}
+	return nextQuery
+}
+
+// skipFailedPage moves an iterator whose last fetch failed past the page it couldn't
+// fetch, as if the page had come back with all of its papers filtered out
+func (it *Iterator) skipFailedPage() {
+	state := it.stateManager.GetState()
+	failed := it.nextPageQuery(state)
+	skipped := &SearchResults{
+		StartIndex:   failed.Start,
+		ItemsPerPage: failed.MaxResults,
+		filtered:     failed.MaxResults,
+	}
+	if state.Results != nil {
+		skipped.TotalCount = state.Results.TotalCount
+	}
+	it.stateManager.Transition(SkipPageAction{Results: skipped})
+}
+
 // nextPaper returns the next paper, handling all state transitions
 func (it *Iterator) nextPaper() (*Paper, error) {
 	state := it.stateManager.GetState()
@@ -259,17 +489,31 @@ func (it *Iterator) nextPaper() (*Paper, error) {
 		if it.needsMoreData(state) {
 			// Check if there's more data available
 			if !it.paginator.HasMoreData(state) {
-				it.stateManager.Transition(FetchAction{Results: state.Results, Error: nil})
+				it.stateManager.Transition(ExhaustAction{})
 				return nil, nil
 			}
 
 			// Create query for next page
-			nextQuery := *it.query
-			nextQuery.Start = it.paginator.CalculateStartIndex(state.CurrentPage, state.Results)
-			nextQuery.MaxResults = it.paginator.CalculateMaxResults(state.TotalFetched)
+			nextQuery := it.nextPageQuery(state)
 
 			// Fetch data
 			results, err := it.fetcher.Fetch(&nextQuery)
+
+			// A page that doesn't start where we asked would repeat papers already yielded
+			if err == nil && !it.paginator.pagesByID() && state.Results != nil && results != nil && results.StartIndex < nextQuery.Start {
+				err = NewAPIError(ErrorTypeParsing, fmt.Sprintf("page starts at index %d, requested %d", results.StartIndex, nextQuery.Start), nil)
+				results = nil
+			}
+
+			// An ID batch with no matches, or a page emptied by client-side filtering,
+			// shouldn't end iteration while more pages remain
+			if err == nil && (results == nil || len(results.Papers) == 0) && (it.paginator.pagesByID() || (results != nil && results.pageLen() > 0)) {
+				skipped := it.stateManager.Transition(SkipPageAction{Results: results})
+				if it.paginator.HasMoreData(skipped) {
+					return it.nextPaper()
+				}
+			}
+
 			newState := it.stateManager.Transition(FetchAction{Results: results, Error: err})
 
 			if newState.Current == StateError {
@@ -286,17 +530,20 @@ func (it *Iterator) nextPaper() (*Paper, error) {
 		if state.Results != nil && state.CurrentIndex < len(state.Results.Papers) {
 			// Check limit before yielding
 			if it.query.Limit > 0 && state.TotalFetched >= it.query.Limit {
-				it.stateManager.Transition(FetchAction{Results: state.Results, Error: nil})
+				it.stateManager.Transition(ExhaustAction{})
 				return nil, nil
 			}
 
 			paper := &state.Results.Papers[state.CurrentIndex]
 			it.stateManager.Transition(ConsumeAction{})
+			if it.query.CopyPapers {
+				paper = paper.Clone()
+			}
 			return paper, nil
 		}
 
 		// No papers available
-		it.stateManager.Transition(FetchAction{Results: state.Results, Error: nil})
+		it.stateManager.Transition(ExhaustAction{})
 		return nil, nil
 
 	default:
@@ -304,7 +551,9 @@ func (it *Iterator) nextPaper() (*Paper, error) {
 	}
 }
 
-// All returns an iterator that yields papers one by one using Go 1.23+ iter pattern
+// All returns an iterator that yields papers one by one using Go 1.23+ iter pattern.
+// The papers point into the iterator's fetched pages and share their slices; set
+// Query.CopyPapers, or Clone them, before modifying them.
 func (it *Iterator) All() iter.Seq[*Paper] {
 	return func(yield func(*Paper) bool) {
 		for {
@@ -338,11 +587,64 @@ func (it *Iterator) AllWithError() iter.Seq2[*Paper, error] {
 	}
 }
 
+// maxConsecutivePageFailures is how many pages in a row AllResilient skips before giving up
+const maxConsecutivePageFailures = 3
+
+// AllResilient is like AllWithError but doesn't stop at a retryable error: it yields the
+// error with a nil paper, skips the page that failed and continues with the next one, so
+// the papers of that page are missing from the results. Iteration stops after a
+// non-retryable error, when the context ends, or after 3 pages in a row have failed.
+// Iteration resumes at the page after the failed one, whose papers don't count toward Limit.
+func (it *Iterator) AllResilient() iter.Seq2[*Paper, error] {
+	return func(yield func(*Paper, error) bool) {
+		failures := 0
+		for {
+			paper, err := it.nextPaper()
+			if err != nil {
+				failures++
+				if !IsRetryable(err) || failures >= maxConsecutivePageFailures || it.fetcher.ctx.Err() != nil {
+					yield(nil, err)
+					return
+				}
+				it.skipFailedPage()
+				if !yield(nil, err) {
+					return
+				}
+				continue
+			}
+			if paper == nil {
+				return
+			}
+			failures = 0
+			if !yield(paper, nil) {
+				return
+			}
+		}
+	}
+}
+
+// Enumerate returns a sequence yielding each paper with its zero-based position in the
+// sequence, for numbered output. Indices count the papers yielded by this range loop, so
+// ranging again after a break starts again at 0 with the next paper.
+func (it *Iterator) Enumerate() iter.Seq2[int, *Paper] {
+	return EnumerateSeq(it.All())
+}
+
 // Values is an alias for All() for compatibility with standard naming conventions
 func (it *Iterator) Values() iter.Seq[*Paper] {
 	return it.All()
 }
 
+// Take returns a sequence yielding at most n papers; nothing is fetched until it is ranged over
+func (it *Iterator) Take(n int) iter.Seq[*Paper] {
+	return TakeSeq(it.All(), n)
+}
+
+// Filter returns a sequence yielding only papers that satisfy pred; nothing is fetched until it is ranged over
+func (it *Iterator) Filter(pred func(*Paper) bool) iter.Seq[*Paper] {
+	return FilterSeq(it.All(), pred)
+}
+
 // Error returns any error that occurred during iteration
 func (it *Iterator) Error() error {
 	return it.stateManager.GetState().Error
@@ -362,6 +664,49 @@ func (it *Iterator) TotalCount() int {
 	return state.Results.TotalCount
 }
 
+// Progress estimates how far the iteration has come, for progress bars and ETAs: fetched
+// is the number of papers yielded so far, total the number the iteration will yield in
+// all (capped by the Limit), and remainingPages the number of requests still to make.
+// total and remainingPages are -1 before the first page is fetched, when the total is
+// unknown. Papers dropped client-side (see Query.StrictDateFilter) and IDs unknown to
+// arXiv make total an overestimate. Progress only reads the iterator's state.
+func (it *Iterator) Progress() (fetched, total, remainingPages int) {
+	state := it.stateManager.GetState()
+	fetched = state.TotalFetched
+	if state.Current == StateExhausted {
+		return fetched, fetched, 0
+	}
+	if state.Results == nil || it.query == nil {
+		return fetched, -1, -1
+	}
+
+	// Papers fetched but not yet yielded, and papers not yet requested
+	var pending, left, pageSize int
+	if it.paginator.pagesByID() {
+		pageSize = it.paginator.IDBatchSize
+		if pageSize <= 0 {
+			pageSize = defaultIDBatchSize
+		}
+		requested := min(state.CurrentPage*pageSize, len(it.query.IDList))
+		pending = max(requested-it.idListPosition(state), 0)
+		left = len(it.query.IDList) - requested
+	} else {
+		pageSize = it.paginator.pageSize(state.Results)
+		pending = len(state.Results.Papers) - state.CurrentIndex
+		left = max(state.Results.TotalCount-it.paginator.nextStart(state.Results), 0)
+	}
+
+	total = fetched + pending + left
+	if it.query.Limit > 0 && total > it.query.Limit {
+		total = max(it.query.Limit, fetched)
+		left = max(total-fetched-pending, 0)
+	}
+	if pageSize > 0 {
+		remainingPages = (left + pageSize - 1) / pageSize
+	}
+	return fetched, total, remainingPages
+}
+
 // CurrentPage returns the current page number (0-based)
 func (it *Iterator) CurrentPage() int {
 	return it.stateManager.GetState().CurrentPage
@@ -375,6 +720,24 @@ func (it *Iterator) Reset() {
 	}
 }
 
+// Drain consumes and discards the remaining papers, up to the limit or the end of
+// the results, leaving the iterator exhausted. Any error is available from Error.
+func (it *Iterator) Drain() {
+	for range it.All() {
+	}
+}
+
+// Close stops the iterator: later iteration yields nothing and fetches nothing.
+// The iterator holds no background resources today, but callers that stop early
+// should still Close it so future versions can release them. An iteration error
+// remains available from Error. Close is idempotent and always returns nil.
+func (it *Iterator) Close() error {
+	if it.stateManager.GetState().Current != StateError {
+		it.stateManager.Transition(ExhaustAction{})
+	}
+	return nil
+}
+
 // WithContext creates a new iterator with a different context
 func (it *Iterator) WithContext(ctx context.Context) *Iterator {
 	return &Iterator{
@@ -399,21 +762,190 @@ func (it *Iterator) ForEach(fn func(*Paper) error) error {
 func (it *Iterator) Collect() ([]*Paper, error) {
 	var papers []*Paper
 	for paper := range it.All() {
+		if papers == nil {
+			// The first page is known now, so size the slice from TotalCount
+			papers = make([]*Paper, 0, it.remainingHint()+1)
+		}
 		papers = append(papers, paper)
 	}
 	return papers, it.Error()
 }
 
-// CollectN returns up to n papers as a slice
+// CollectConcurrent returns all remaining papers like Collect, but once the first page
+// reveals TotalCount it fetches the remaining pages with up to workers concurrent requests
+// and stitches them back in order. Every request still waits for the client's rate limiter,
+// so the gain comes from overlapping slow responses at the cost of a heavier API load.
+// ID list queries and workers < 2 fall back to Collect. On error it returns the papers
+// before the first failed page; the iterator is left exhausted or in the error state.
+func (it *Iterator) CollectConcurrent(ctx context.Context, workers int) ([]*Paper, error) {
+	it.fetcher = it.fetcher.WithContext(ctx)
+	if workers < 2 || it.query == nil || it.paginator.pagesByID() {
+		return it.Collect()
+	}
+
+	// Finish the current page through the iterator, fetching it first if needed
+	var papers []*Paper
+	for {
+		paper, err := it.nextPaper()
+		if paper == nil {
+			return papers, err
+		}
+		papers = append(papers, paper)
+		if state := it.stateManager.GetState(); state.CurrentIndex >= len(state.Results.Papers) {
+			break
+		}
+	}
+	state := it.stateManager.GetState()
+	if !it.paginator.HasMoreData(state) {
+		it.stateManager.Transition(ExhaustAction{})
+		return papers, nil
+	}
+
+	// Plan the remaining pages, each as large as the page size arXiv reported
+	pageSize := it.paginator.pageSize(state.Results)
+	if pageSize <= 0 {
+		pageSize = state.Results.pageLen()
+	}
+	end := state.Results.TotalCount
+	remaining := -1
+	if it.query.Limit > 0 {
+		remaining = it.query.Limit - state.TotalFetched
+		if !it.query.filtersResults() {
+			end = min(end, it.paginator.nextStart(state.Results)+remaining)
+		}
+	}
+	var pages []Query
+	for start := it.paginator.nextStart(state.Results); start < end; start += pageSize {
+		page := *it.query
+		page.Start = start
+		page.MaxResults = min(pageSize, end-start)
+		pages = append(pages, page)
+	}
+
+	pageResults, failedPage, err := fetchPages(ctx, it.fetcher.client, pages, workers)
+
+	consumed := 0
+	for _, results := range pageResults[:failedPage] {
+		for i := range results.Papers {
+			if remaining >= 0 && consumed >= remaining {
+				break
+			}
+			paper := &results.Papers[i]
+			if it.query.CopyPapers {
+				paper = paper.Clone()
+			}
+			papers = append(papers, paper)
+			consumed++
+		}
+	}
+	it.stateManager.Transition(ExhaustAction{Consumed: consumed})
+	if err != nil {
+		it.stateManager.Transition(FetchAction{Error: err})
+	}
+	return papers, err
+}
+
+// fetchPages runs the page queries with up to workers concurrent requests. It returns the
+// results by page, the index of the first page that failed (len(pages) if none did) and
+// the error that stopped the fetch; pages after a failure may be missing.
+func fetchPages(ctx context.Context, client Searcher, pages []Query, workers int) ([]*SearchResults, int, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make([]*SearchResults, len(pages))
+	failedPage := len(pages)
+	var firstErr error
+	var mu sync.Mutex
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for range min(workers, len(pages)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				pageResults, err := client.Search(ctx, &pages[i])
+				if err != nil {
+					mu.Lock()
+					failedPage = min(failedPage, i)
+					if firstErr == nil {
+						firstErr = err
+					}
+					mu.Unlock()
+					cancel()
+					continue
+				}
+				results[i] = pageResults
+			}
+		}()
+	}
+
+	// Stop handing out pages once one has failed
+feed:
+	for i := range pages {
+		select {
+		case indexes <- i:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(indexes)
+	wg.Wait()
+
+	if firstErr == nil && ctx.Err() != nil {
+		// The caller's context ended before every page was handed out
+		for i, pageResults := range results {
+			if pageResults == nil {
+				failedPage = i
+				firstErr = newContextError(ctx)
+				break
+			}
+		}
+	}
+	return results, failedPage, firstErr
+}
+
+// CollectInto appends all remaining papers to dst and returns the extended slice.
+// Passing a slice with enough capacity avoids reallocations for large result sets.
+func (it *Iterator) CollectInto(dst []*Paper) ([]*Paper, error) {
+	for paper := range it.All() {
+		dst = append(dst, paper)
+	}
+	return dst, it.Error()
+}
+
+// remainingHint estimates how many papers are left to yield, capped at maxCapacityHint
+func (it *Iterator) remainingHint() int {
+	state := it.stateManager.GetState()
+	if state.Results == nil {
+		return 0
+	}
+
+	remaining := state.Results.TotalCount - (state.Results.StartIndex + state.CurrentIndex)
+	if it.query.Limit > 0 && it.query.Limit-state.TotalFetched < remaining {
+		remaining = it.query.Limit - state.TotalFetched
+	}
+	if remaining < 0 {
+		return 0
+	}
+	return min(remaining, maxCapacityHint)
+}
+
+// CollectN returns up to n papers as a slice. It consumes only the papers it returns, so
+// a later call or range over the iterator continues with the next one.
 func (it *Iterator) CollectN(n int) ([]*Paper, error) {
 	var papers []*Paper
 	count := 0
+	if n <= 0 {
+		return papers, it.Error()
+	}
 	for paper := range it.All() {
+		papers = append(papers, paper)
+		count++
+		// Stop before pulling another paper so it isn't consumed and dropped
 		if count >= n {
 			break
 		}
-		papers = append(papers, paper)
-		count++
 	}
 	return papers, it.Error()
 }
@@ -430,6 +962,23 @@ func ForEachSeq[T any](seq iter.Seq[T], fn func(T) error) error {
 	return nil
 }
 
+// ForEachSeqCtx applies a function to each element in an iter.Seq, returning ctx.Err()
+// as soon as ctx is done between elements
+func ForEachSeqCtx[T any](ctx context.Context, seq iter.Seq[T], fn func(context.Context, T) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	for item := range seq {
+		if err := fn(ctx, item); err != nil {
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // CollectSeq collects all elements from an iter.Seq into a slice
 func CollectSeq[T any](seq iter.Seq[T]) []T {
 	var result []T
@@ -439,6 +988,17 @@ func CollectSeq[T any](seq iter.Seq[T]) []T {
 	return result
 }
 
+// CollectSeqCtx collects elements from an iter.Seq into a slice until seq ends or ctx is done.
+// On cancellation it returns the elements collected so far together with ctx.Err().
+func CollectSeqCtx[T any](ctx context.Context, seq iter.Seq[T]) ([]T, error) {
+	var result []T
+	err := ForEachSeqCtx(ctx, seq, func(_ context.Context, item T) error {
+		result = append(result, item)
+		return nil
+	})
+	return result, err
+}
+
 // CollectNSeq collects up to n elements from an iter.Seq into a slice
 func CollectNSeq[T any](seq iter.Seq[T], n int) []T {
 	var result []T
@@ -469,6 +1029,19 @@ func TakeSeq[T any](seq iter.Seq[T], n int) iter.Seq[T] {
 	}
 }
 
+// EnumerateSeq returns an iterator that yields each element of seq with its zero-based index
+func EnumerateSeq[T any](seq iter.Seq[T]) iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		i := 0
+		for item := range seq {
+			if !yield(i, item) {
+				return
+			}
+			i++
+		}
+	}
+}
+
 // FilterSeq returns an iterator that yields only elements that satisfy the predicate
 func FilterSeq[T any](seq iter.Seq[T], predicate func(T) bool) iter.Seq[T] {
 	return func(yield func(T) bool) {
@@ -481,3 +1054,68 @@ func FilterSeq[T any](seq iter.Seq[T], predicate func(T) bool) iter.Seq[T] {
 		}
 	}
 }
+
+// MapErrSeq returns an iterator that yields fn applied to each element along with its error.
+// Iteration continues after an error; the consumer decides whether to stop.
+func MapErrSeq[T, U any](seq iter.Seq[T], fn func(T) (U, error)) iter.Seq2[U, error] {
+	return func(yield func(U, error) bool) {
+		for item := range seq {
+			if !yield(fn(item)) {
+				return
+			}
+		}
+	}
+}
+
+// ParallelForEachSeq applies fn to each element of seq using up to workers goroutines.
+// The order of fn invocations is not guaranteed. The first error stops pulling from seq,
+// remaining queued elements are skipped, and that error is returned.
+func ParallelForEachSeq[T any](seq iter.Seq[T], workers int, fn func(T) error) error {
+	if workers < 1 {
+		workers = 1
+	}
+
+	items := make(chan T)
+	done := make(chan struct{})
+	var once sync.Once
+	var firstErr error
+	fail := func(err error) {
+		once.Do(func() {
+			firstErr = err
+			close(done)
+		})
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for item := range items {
+				select {
+				case <-done:
+					continue
+				default:
+				}
+				if err := fn(item); err != nil {
+					fail(err)
+				}
+			}
+		}()
+	}
+
+	// Feed workers from the calling goroutine so seq itself is never used concurrently
+	func() {
+		defer close(items)
+		for item := range seq {
+			select {
+			case items <- item:
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	wg.Wait()
+	return firstErr
+}
@@ -2,19 +2,118 @@ package arxiv
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 )
 
+// generateFeed builds an Atom feed with n entries numbered from start
+func generateFeed(total, start, n int) string {
+	var b strings.Builder
+	b.WriteString(`<?xml version="1.0" encoding="UTF-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom">`)
+	fmt.Fprintf(&b, `
+  <opensearch:totalResults xmlns:opensearch="http://a9.com/-/spec/opensearch/1.1/">%d</opensearch:totalResults>
+  <opensearch:startIndex xmlns:opensearch="http://a9.com/-/spec/opensearch/1.1/">%d</opensearch:startIndex>
+  <opensearch:itemsPerPage xmlns:opensearch="http://a9.com/-/spec/opensearch/1.1/">%d</opensearch:itemsPerPage>`, total, start, n)
+	for i := start; i < start+n; i++ {
+		fmt.Fprintf(&b, `
+  <entry>
+    <id>http://arxiv.org/abs/2301.%05dv1</id>
+    <title>Test Paper %d</title>
+    <summary>Abstract %d</summary>
+    <published>2023-01-01T00:00:00Z</published>
+    <updated>2023-01-01T00:00:00Z</updated>
+    <author><name>Author %d</name></author>
+    <category term="cs.AI" scheme="http://arxiv.org/schemas/atom"/>
+  </entry>`, i, i, i, i)
+	}
+	b.WriteString("\n</feed>")
+	return b.String()
+}
+
+// newPagingServer serves generateFeed pages honoring the start and max_results parameters
+func newPagingServer(total int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start, _ := strconv.Atoi(r.URL.Query().Get("start"))
+		maxResults, _ := strconv.Atoi(r.URL.Query().Get("max_results"))
+		n := min(maxResults, total-start)
+		if n < 0 {
+			n = 0
+		}
+		w.Header().Set("Content-Type", "application/xml")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(generateFeed(total, start, n)))
+	}))
+}
+
+// newIDListServer serves one entry per requested id_list ID, skipping IDs listed in missing
+func newIDListServer(requests *[][]string, missing ...string) *httptest.Server {
+	var mu sync.Mutex
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ids := strings.Split(r.URL.Query().Get("id_list"), ",")
+		mu.Lock()
+		*requests = append(*requests, ids)
+		mu.Unlock()
+
+		var b strings.Builder
+		found := 0
+		for _, id := range ids {
+			if slices.Contains(missing, id) {
+				continue
+			}
+			found++
+			fmt.Fprintf(&b, `
+  <entry>
+    <id>http://arxiv.org/abs/%sv1</id>
+    <title>Paper %s</title>
+    <published>2023-01-01T00:00:00Z</published>
+    <updated>2023-01-01T00:00:00Z</updated>
+  </entry>`, id, id)
+		}
+		w.Header().Set("Content-Type", "application/xml")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintf(w, `<?xml version="1.0" encoding="UTF-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom">
+  <opensearch:totalResults xmlns:opensearch="http://a9.com/-/spec/opensearch/1.1/">%d</opensearch:totalResults>
+  <opensearch:startIndex xmlns:opensearch="http://a9.com/-/spec/opensearch/1.1/">0</opensearch:startIndex>
+  <opensearch:itemsPerPage xmlns:opensearch="http://a9.com/-/spec/opensearch/1.1/">%d</opensearch:itemsPerPage>%s
+</feed>`, found, found, b.String())
+	}))
+}
+
+// testIDs returns n distinct new-style arXiv IDs
+func testIDs(n int) []string {
+	ids := make([]string, n)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("2301.%05d", i)
+	}
+	return ids
+}
+
+// newFastClient creates a client pointing at url with negligible rate limiting
+func newFastClient(url string) *Client {
+	client := NewClientWithOptions(ClientOptions{RateLimit: time.Nanosecond})
+	client.baseURL = url
+	return client
+}
+
 func TestIterator_BasicIteration(t *testing.T) {
 	// Create a mock server
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// Mock response with 2 papers
 		response := `<?xml version="1.0" encoding="UTF-8"?>
 <feed xmlns="http://www.w3.org/2005/Atom">
-  <opensearch:totalResults xmlns:opensearch="http://a9.com/-/spec/opensearch/1.1/">100</opensearch:totalResults>
+  <opensearch:totalResults xmlns:opensearch="http://a9.com/-/spec/opensearch/1.1/">2</opensearch:totalResults>
   <opensearch:startIndex xmlns:opensearch="http://a9.com/-/spec/opensearch/1.1/">0</opensearch:startIndex>
   <opensearch:itemsPerPage xmlns:opensearch="http://a9.com/-/spec/opensearch/1.1/">2</opensearch:itemsPerPage>
   <entry>
@@ -314,6 +413,84 @@ func TestIterator_Collect(t *testing.T) {
 	}
 }
 
+func TestIterator_CollectInto(t *testing.T) {
+	server := newPagingServer(25)
+	defer server.Close()
+
+	client := newFastClient(server.URL)
+	iter := client.NewQuery().SearchQuery("test").MaxResults(10).Iterator(context.Background())
+
+	dst := make([]*Paper, 0, 25)
+	papers, err := iter.CollectInto(dst)
+	if err != nil {
+		t.Fatalf("CollectInto error: %v", err)
+	}
+
+	if len(papers) != 25 {
+		t.Fatalf("Expected 25 papers, got %d", len(papers))
+	}
+
+	if cap(papers) != 25 {
+		t.Errorf("Expected papers to reuse the provided capacity 25, got %d", cap(papers))
+	}
+
+	for i, paper := range papers {
+		expected := fmt.Sprintf("Test Paper %d", i)
+		if paper.Title != expected {
+			t.Errorf("Expected paper %d title '%s', got '%s'", i, expected, paper.Title)
+		}
+	}
+}
+
+func TestIterator_CollectPreallocates(t *testing.T) {
+	server := newPagingServer(25)
+	defer server.Close()
+
+	client := newFastClient(server.URL)
+	iter := client.NewQuery().SearchQuery("test").MaxResults(10).Limit(15).Iterator(context.Background())
+
+	papers, err := iter.Collect()
+	if err != nil {
+		t.Fatalf("Collect error: %v", err)
+	}
+
+	if len(papers) != 15 {
+		t.Fatalf("Expected 15 papers, got %d", len(papers))
+	}
+
+	if cap(papers) != 15 {
+		t.Errorf("Expected capacity sized from Limit (15), got %d", cap(papers))
+	}
+}
+
+func BenchmarkIterator_Collect(b *testing.B) {
+	server := newPagingServer(2000)
+	defer server.Close()
+
+	client := newFastClient(server.URL)
+
+	b.Run("Collect", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			iter := client.NewQuery().SearchQuery("test").MaxResults(500).Iterator(context.Background())
+			if _, err := iter.Collect(); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("CollectInto", func(b *testing.B) {
+		b.ReportAllocs()
+		dst := make([]*Paper, 0, 2000)
+		for i := 0; i < b.N; i++ {
+			iter := client.NewQuery().SearchQuery("test").MaxResults(500).Iterator(context.Background())
+			if _, err := iter.CollectInto(dst[:0]); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
 func TestIterator_CollectN(t *testing.T) {
 	// Create a mock server
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -381,6 +558,18 @@ func TestIterator_CollectN(t *testing.T) {
 	if papers[1].Title != "Test Paper 2" {
 		t.Errorf("Expected second paper title 'Test Paper 2', got '%s'", papers[1].Title)
 	}
+
+	// CollectN consumes exactly the papers it returns, so the next call picks up after them
+	if papers, err := iter.CollectN(0); err != nil || len(papers) != 0 {
+		t.Errorf("Expected CollectN(0) to return nothing, got %d papers, %v", len(papers), err)
+	}
+	rest, err := iter.CollectN(5)
+	if err != nil {
+		t.Errorf("CollectN error: %v", err)
+	}
+	if len(rest) != 1 || rest[0].Title != "Test Paper 3" {
+		t.Errorf("Expected the remaining 'Test Paper 3', got %d papers", len(rest))
+	}
 }
 
 func TestIterator_WithContext(t *testing.T) {
@@ -506,6 +695,69 @@ func TestIterator_LimitFunctionality(t *testing.T) {
 	}
 }
 
+func TestIterator_LimitSmallerFinalPage(t *testing.T) {
+	var pages []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start, _ := strconv.Atoi(r.URL.Query().Get("start"))
+		maxResults, _ := strconv.Atoi(r.URL.Query().Get("max_results"))
+		pages = append(pages, fmt.Sprintf("%d+%d", start, maxResults))
+		w.Header().Set("Content-Type", "application/xml")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(generateFeed(20, start, min(maxResults, 20-start))))
+	}))
+	defer server.Close()
+
+	client := newFastClient(server.URL)
+	papers, err := client.NewQuery().SearchQuery("test").MaxResults(4).Limit(10).Iterator(context.Background()).Collect()
+	if err != nil {
+		t.Fatalf("Collect error: %v", err)
+	}
+
+	// Every paper in order, none skipped or repeated
+	if len(papers) != 10 {
+		t.Fatalf("Expected 10 papers, got %d", len(papers))
+	}
+	for i, paper := range papers {
+		if expected := fmt.Sprintf("2301.%05dv1", i); paper.ID != expected {
+			t.Errorf("Expected paper %d to be %s, got %s", i, expected, paper.ID)
+		}
+	}
+	if !slices.Equal(pages, []string{"0+4", "4+4", "8+2"}) {
+		t.Errorf("Expected pages 0+4, 4+4, 8+2, got %v", pages)
+	}
+}
+
+func TestIterator_InitialStart(t *testing.T) {
+	var starts []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		starts = append(starts, r.URL.Query().Get("start"))
+		start, _ := strconv.Atoi(r.URL.Query().Get("start"))
+		maxResults, _ := strconv.Atoi(r.URL.Query().Get("max_results"))
+		w.Header().Set("Content-Type", "application/xml")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(generateFeed(20, start, min(maxResults, 20-start))))
+	}))
+	defer server.Close()
+
+	client := newFastClient(server.URL)
+	iter := client.NewQuery().SearchQuery("test").Start(10).MaxResults(4).Iterator(context.Background())
+	if start := iter.State().Start; start != 10 {
+		t.Errorf("Expected an unstarted checkpoint at 10, got %d", start)
+	}
+
+	papers, err := iter.Collect()
+	if err != nil {
+		t.Fatalf("Collect error: %v", err)
+	}
+
+	if len(starts) == 0 || starts[0] != "10" {
+		t.Fatalf("Expected the first request to start at 10, got %v", starts)
+	}
+	if len(papers) != 10 || papers[0].ID != "2301.00010v1" || papers[9].ID != "2301.00019v1" {
+		t.Errorf("Expected papers 10 to 19, got %d papers", len(papers))
+	}
+}
+
 func TestIterator_InvalidQuery(t *testing.T) {
 	client := NewClient()
 
@@ -853,7 +1105,679 @@ func TestIterator_HelperFunctions(t *testing.T) {
 	}
 }
 
+func TestIterator_TakeAndFilter(t *testing.T) {
+	requests := 0
+	server := newPagingServer(30)
+	defer server.Close()
+	counting := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		server.Config.Handler.ServeHTTP(w, r)
+	}))
+	defer counting.Close()
+
+	client := newFastClient(counting.URL)
+	iter := client.NewQuery().SearchQuery("test").MaxResults(10).Iterator(context.Background())
+
+	taken := iter.Take(3)
+	filtered := iter.Filter(TitleContains("Paper 2"))
+	if requests != 0 {
+		t.Fatalf("Expected no requests before ranging, got %d", requests)
+	}
+
+	first := CollectSeq(taken)
+	if len(first) != 3 || first[0].Title != "Test Paper 0" {
+		t.Errorf("Expected first 3 papers, got %v", first)
+	}
+
+	iter.Reset()
+	matches := CollectSeq(filtered)
+	// "Test Paper 2" and "Test Paper 20".."Test Paper 29"
+	if len(matches) != 11 {
+		t.Errorf("Expected 11 filtered papers, got %d", len(matches))
+	}
+}
+
+func TestIterator_Enumerate(t *testing.T) {
+	server := newPagingServer(12)
+	defer server.Close()
+
+	client := newFastClient(server.URL)
+	iter := client.NewQuery().SearchQuery("test").MaxResults(5).Iterator(context.Background())
+
+	// Indices run from 0 across page boundaries
+	for i, paper := range iter.Enumerate() {
+		if expected := fmt.Sprintf("Test Paper %d", i); paper.Title != expected {
+			t.Errorf("Expected index %d to be %q, got %q", i, expected, paper.Title)
+		}
+		if i == 6 {
+			break
+		}
+	}
+
+	// A new range continues with the next paper, counting from 0 again
+	var indices []int
+	for i, paper := range iter.Enumerate() {
+		if i == 0 && paper.Title != "Test Paper 7" {
+			t.Errorf("Expected to resume at Test Paper 7, got %q", paper.Title)
+		}
+		indices = append(indices, i)
+	}
+	if !slices.Equal(indices, []int{0, 1, 2, 3, 4}) {
+		t.Errorf("Expected indices 0-4 for the remaining papers, got %v", indices)
+	}
+}
+
+func TestEnumerateSeq(t *testing.T) {
+	var indices []int
+	var values []string
+	for i, value := range EnumerateSeq(slices.Values([]string{"a", "b", "c", "d"})) {
+		indices = append(indices, i)
+		values = append(values, value)
+		if value == "c" {
+			break
+		}
+	}
+
+	if !slices.Equal(indices, []int{0, 1, 2}) || !slices.Equal(values, []string{"a", "b", "c"}) {
+		t.Errorf("Expected [0 1 2] [a b c], got %v %v", indices, values)
+	}
+	for range EnumerateSeq(slices.Values([]string{})) {
+		t.Error("Expected no elements from an empty sequence")
+	}
+}
+
+func TestIterator_Progress(t *testing.T) {
+	server := newPagingServer(95)
+	defer server.Close()
+	client := newFastClient(server.URL)
+
+	checkProgress := func(iter *Iterator, fetched, total, remainingPages int) {
+		t.Helper()
+		f, tot, pages := iter.Progress()
+		if f != fetched || tot != total || pages != remainingPages {
+			t.Errorf("Expected progress (%d, %d, %d), got (%d, %d, %d)", fetched, total, remainingPages, f, tot, pages)
+		}
+	}
+
+	consume := func(iter *Iterator, n int) {
+		t.Helper()
+		count := 0
+		for range iter.All() {
+			if count++; count == n {
+				break
+			}
+		}
+	}
+
+	iter := client.NewQuery().SearchQuery("test").MaxResults(10).Iterator(context.Background())
+	checkProgress(iter, 0, -1, -1)
+
+	// One page of 10 fetched, one paper consumed: pages at 10, 20, ... 90 remain
+	consume(iter, 1)
+	checkProgress(iter, 1, 95, 9)
+
+	consume(iter, 14)
+	checkProgress(iter, 15, 95, 8)
+
+	iter.Drain()
+	checkProgress(iter, 95, 95, 0)
+
+	// The limit caps the total and the pages still needed
+	limited := client.NewQuery().SearchQuery("test").MaxResults(10).Limit(25).Iterator(context.Background())
+	consume(limited, 1)
+	checkProgress(limited, 1, 25, 2)
+
+	// ID lists progress by batches
+	var requests [][]string
+	idServer := newIDListServer(&requests)
+	defer idServer.Close()
+	byID := newFastClient(idServer.URL).NewQuery().IDList(testIDs(250)...).Iterator(context.Background())
+	consume(byID, 30)
+	checkProgress(byID, 30, 250, 2)
+}
+
+func TestIterator_AllResilient(t *testing.T) {
+	newServer := func(failing func(start int) int) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start, _ := strconv.Atoi(r.URL.Query().Get("start"))
+			maxResults, _ := strconv.Atoi(r.URL.Query().Get("max_results"))
+			if status := failing(start); status != 0 {
+				w.WriteHeader(status)
+				return
+			}
+			w.Header().Set("Content-Type", "application/xml")
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(generateFeed(50, start, min(maxResults, 50-start))))
+		}))
+	}
+	collect := func(server *httptest.Server) ([]*Paper, []error) {
+		client := NewClientWithOptions(ClientOptions{RateLimit: time.Nanosecond, RetryAttempts: 1})
+		client.baseURL = server.URL
+		iter := client.NewQuery().SearchQuery("test").MaxResults(10).Iterator(context.Background())
+
+		var papers []*Paper
+		var errs []error
+		for paper, err := range iter.AllResilient() {
+			if err != nil {
+				if paper != nil {
+					t.Errorf("Expected a nil paper with error %v", err)
+				}
+				errs = append(errs, err)
+				continue
+			}
+			papers = append(papers, paper)
+		}
+		return papers, errs
+	}
+
+	// The middle page fails; the pages after it still arrive
+	server := newServer(func(start int) int {
+		if start == 20 {
+			return http.StatusInternalServerError
+		}
+		return 0
+	})
+	defer server.Close()
+	papers, errs := collect(server)
+	if len(errs) != 1 || !IsRetryable(errs[0]) {
+		t.Errorf("Expected one retryable error, got %v", errs)
+	}
+	if len(papers) != 40 || papers[19].ID != "2301.00019v1" || papers[20].ID != "2301.00030v1" {
+		t.Errorf("Expected the 40 papers outside the failed page, got %d", len(papers))
+	}
+
+	// A non-retryable error ends iteration
+	invalid := newServer(func(start int) int {
+		if start == 10 {
+			return http.StatusBadRequest
+		}
+		return 0
+	})
+	defer invalid.Close()
+	papers, errs = collect(invalid)
+	if len(papers) != 10 || len(errs) != 1 || !IsInvalidQuery(errs[0]) {
+		t.Errorf("Expected 10 papers and an invalid query error, got %d, %v", len(papers), errs)
+	}
+
+	// Consecutive failures are capped
+	down := newServer(func(start int) int {
+		if start > 0 {
+			return http.StatusInternalServerError
+		}
+		return 0
+	})
+	defer down.Close()
+	papers, errs = collect(down)
+	if len(papers) != 10 || len(errs) != maxConsecutivePageFailures {
+		t.Errorf("Expected 10 papers and %d errors, got %d, %d", maxConsecutivePageFailures, len(papers), len(errs))
+	}
+}
+
+func TestIterator_CopyPapers(t *testing.T) {
+	server := newPagingServer(10)
+	defer server.Close()
+	client := newFastClient(server.URL)
+
+	// mutateFirst changes the first yielded paper and returns the iterator's copy of it
+	mutateFirst := func(copyPapers bool) Paper {
+		iter := client.Iterator(context.Background(), &Query{SearchQuery: "test", MaxResults: 5, CopyPapers: copyPapers})
+		for paper := range iter.All() {
+			paper.Title = "Mutated"
+			paper.Authors[0].Name = "Mallory"
+			break
+		}
+		return iter.stateManager.GetState().Results.Papers[0]
+	}
+
+	// By default the yielded pointers alias the fetched page
+	if stored := mutateFirst(false); stored.Title != "Mutated" {
+		t.Errorf("Expected the yielded paper to alias the page, got %q", stored.Title)
+	}
+
+	stored := mutateFirst(true)
+	if stored.Title != "Test Paper 0" || stored.Authors[0].Name != "Author 0" {
+		t.Errorf("Expected CopyPapers to protect the page, got %q by %q", stored.Title, stored.Authors[0].Name)
+	}
+}
+
+func TestIterator_ServerCappedPageSize(t *testing.T) {
+	// The server answers at most 2 entries per page, below the requested max_results
+	var starts []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		starts = append(starts, r.URL.Query().Get("start"))
+		start, _ := strconv.Atoi(r.URL.Query().Get("start"))
+		maxResults, _ := strconv.Atoi(r.URL.Query().Get("max_results"))
+		n := max(min(maxResults, 2, 7-start), 0)
+		w.Header().Set("Content-Type", "application/xml")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(generateFeed(7, start, n)))
+	}))
+	defer server.Close()
+
+	client := newFastClient(server.URL)
+	papers, err := client.NewQuery().SearchQuery("test").MaxResults(5).Iterator(context.Background()).Collect()
+	if err != nil {
+		t.Fatalf("Collect error: %v", err)
+	}
+
+	if len(papers) != 7 {
+		t.Fatalf("Expected all 7 papers despite the capped page size, got %d", len(papers))
+	}
+	for i, paper := range papers {
+		if expected := fmt.Sprintf("2301.%05dv1", i); paper.ID != expected {
+			t.Errorf("Expected paper %d to be %s, got %s", i, expected, paper.ID)
+		}
+	}
+	if !slices.Equal(starts, []string{"", "2", "4", "6"}) {
+		t.Errorf("Expected pages to start at 0, 2, 4, 6, got %q", starts)
+	}
+}
+
+func TestIterator_PageStartMismatch(t *testing.T) {
+	// The server ignores start and keeps answering with the first page
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/xml")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(generateFeed(6, 0, 3)))
+	}))
+	defer server.Close()
+
+	client := newFastClient(server.URL)
+	iter := client.NewQuery().SearchQuery("test").MaxResults(3).Iterator(context.Background())
+	papers, err := iter.Collect()
+	if !errors.Is(err, ErrParsing) {
+		t.Fatalf("Expected a parsing error for the repeated page, got %v", err)
+	}
+	if len(papers) != 3 || papers[2].ID != "2301.00002v1" {
+		t.Errorf("Expected the 3 papers of the first page, got %d", len(papers))
+	}
+	if iter.Error() == nil {
+		t.Error("Expected the iterator to be in the error state")
+	}
+}
+
+func TestIterator_IDListBatching(t *testing.T) {
+	var requests [][]string
+	server := newIDListServer(&requests)
+	defer server.Close()
+
+	client := newFastClient(server.URL)
+	ids := testIDs(250)
+	iter := client.NewQuery().IDList(ids...).Iterator(context.Background())
+
+	papers, err := iter.Collect()
+	if err != nil {
+		t.Fatalf("Collect error: %v", err)
+	}
+
+	if len(requests) != 3 {
+		t.Fatalf("Expected 3 id_list requests, got %d", len(requests))
+	}
+	for i, size := range []int{100, 100, 50} {
+		if len(requests[i]) != size {
+			t.Errorf("Expected request %d to carry %d IDs, got %d", i, size, len(requests[i]))
+		}
+	}
+
+	if len(papers) != 250 {
+		t.Fatalf("Expected 250 papers, got %d", len(papers))
+	}
+	for i, paper := range papers {
+		if paper.ID != ids[i]+"v1" {
+			t.Fatalf("Expected paper %d to be %sv1, got %s", i, ids[i], paper.ID)
+		}
+	}
+}
+
+func TestIterator_IDsInRange(t *testing.T) {
+	var requests [][]string
+	server := newIDListServer(&requests, "2301.00150")
+	defer server.Close()
+
+	client := newFastClient(server.URL)
+	papers, err := client.NewQuery().IDList(IDsInRange("2301", 101, 250)...).Iterator(context.Background()).Collect()
+	if err != nil {
+		t.Fatalf("Collect error: %v", err)
+	}
+
+	if len(requests) != 2 || requests[0][0] != "2301.00101" || requests[1][49] != "2301.00250" {
+		t.Fatalf("Expected the range in 2 id_list batches, got %d requests", len(requests))
+	}
+	if len(papers) != 149 {
+		t.Errorf("Expected 149 papers (one ID unassigned), got %d", len(papers))
+	}
+}
+
+func TestIterator_IDListBatchWithNoMatches(t *testing.T) {
+	var requests [][]string
+	ids := testIDs(5)
+	server := newIDListServer(&requests, ids[2], ids[3])
+	defer server.Close()
+
+	client := newFastClient(server.URL)
+	query := &Query{IDList: ids}
+	iter := client.Iterator(context.Background(), query)
+	iter.paginator.IDBatchSize = 2
+
+	papers, err := iter.Collect()
+	if err != nil {
+		t.Fatalf("Collect error: %v", err)
+	}
+
+	if len(requests) != 3 {
+		t.Errorf("Expected 3 requests, got %d", len(requests))
+	}
+	if len(papers) != 3 {
+		t.Fatalf("Expected 3 papers despite an empty batch, got %d", len(papers))
+	}
+	if papers[2].ID != ids[4]+"v1" {
+		t.Errorf("Expected last paper %sv1, got %s", ids[4], papers[2].ID)
+	}
+}
+
+// resumeFromJSON round-trips checkpoint through JSON and resumes it on client
+func resumeFromJSON(t *testing.T, client *Client, checkpoint IteratorCheckpoint) *Iterator {
+	t.Helper()
+	data, err := json.Marshal(checkpoint)
+	if err != nil {
+		t.Fatalf("Failed to marshal checkpoint: %v", err)
+	}
+	var restored IteratorCheckpoint
+	if err := json.Unmarshal(data, &restored); err != nil {
+		t.Fatalf("Failed to unmarshal checkpoint: %v", err)
+	}
+	return client.ResumeIterator(context.Background(), restored)
+}
+
+func TestIterator_CheckpointResume(t *testing.T) {
+	server := newPagingServer(7)
+	defer server.Close()
+
+	client := newFastClient(server.URL)
+	iter := client.NewQuery().SearchQuery("test").MaxResults(3).Iterator(context.Background())
+
+	var ids []string
+	for paper := range iter.All() {
+		ids = append(ids, paper.ID)
+		if len(ids) == 4 {
+			break
+		}
+	}
+
+	checkpoint := iter.State()
+	if checkpoint.Start != 4 || checkpoint.TotalFetched != 4 {
+		t.Errorf("Expected checkpoint at start 4 with 4 fetched, got %+v", checkpoint)
+	}
+
+	resumed := resumeFromJSON(t, client, checkpoint)
+	for paper := range resumed.All() {
+		ids = append(ids, paper.ID)
+	}
+	if err := resumed.Error(); err != nil {
+		t.Fatalf("Resumed iteration error: %v", err)
+	}
+
+	if len(ids) != 7 {
+		t.Fatalf("Expected 7 papers across both runs, got %d: %v", len(ids), ids)
+	}
+	for i, id := range ids {
+		if expected := fmt.Sprintf("2301.%05dv1", i); id != expected {
+			t.Errorf("Paper %d: expected %s, got %s", i, expected, id)
+		}
+	}
+	if resumed.TotalFetched() != 7 {
+		t.Errorf("Expected resumed TotalFetched 7, got %d", resumed.TotalFetched())
+	}
+
+	// A checkpoint of a finished iterator resumes as exhausted
+	finished := resumeFromJSON(t, client, resumed.State())
+	if papers, _ := finished.Collect(); len(papers) != 0 {
+		t.Errorf("Expected no papers after resuming a finished iterator, got %d", len(papers))
+	}
+}
+
+func TestIterator_CheckpointResumeWithLimit(t *testing.T) {
+	server := newPagingServer(20)
+	defer server.Close()
+
+	client := newFastClient(server.URL)
+	iter := client.NewQuery().SearchQuery("test").MaxResults(3).Limit(5).Iterator(context.Background())
+
+	if _, err := iter.CollectN(2); err != nil {
+		t.Fatalf("CollectN error: %v", err)
+	}
+
+	papers, err := resumeFromJSON(t, client, iter.State()).Collect()
+	if err != nil {
+		t.Fatalf("Collect error: %v", err)
+	}
+	if len(papers) != 3 {
+		t.Errorf("Expected the limit to leave 3 papers after resuming, got %d", len(papers))
+	}
+}
+
+func TestIterator_CheckpointResumeIDList(t *testing.T) {
+	var requests [][]string
+	ids := testIDs(5)
+	server := newIDListServer(&requests, ids[1])
+	defer server.Close()
+
+	client := NewClientWithOptions(ClientOptions{RateLimit: time.Nanosecond, IDBatchSize: 3})
+	client.baseURL = server.URL
+	iter := client.NewQuery().IDList(ids...).Iterator(context.Background())
+
+	// Yields ids[0] and ids[2]; ids[1] is unknown
+	first, err := iter.CollectN(2)
+	if err != nil {
+		t.Fatalf("CollectN error: %v", err)
+	}
+	if len(first) != 2 || first[1].ID != ids[2]+"v1" {
+		t.Fatalf("Unexpected first papers: %v", first)
+	}
+
+	checkpoint := iter.State()
+	if !slices.Equal(checkpoint.Query.IDList, ids[3:]) {
+		t.Errorf("Expected remaining IDs %v, got %v", ids[3:], checkpoint.Query.IDList)
+	}
+
+	requests = nil
+	rest, err := resumeFromJSON(t, client, checkpoint).Collect()
+	if err != nil {
+		t.Fatalf("Collect error: %v", err)
+	}
+	if len(rest) != 2 || rest[0].ID != ids[3]+"v1" || rest[1].ID != ids[4]+"v1" {
+		t.Errorf("Expected the last two papers after resuming, got %v", rest)
+	}
+	if len(requests) != 1 {
+		t.Errorf("Expected 1 request after resuming, got %d", len(requests))
+	}
+}
+
+func TestMapErrSeq(t *testing.T) {
+	seq := MapErrSeq(slices.Values([]string{"1", "x", "3"}), strconv.Atoi)
+
+	var values []int
+	var errs []error
+	for value, err := range seq {
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		values = append(values, value)
+	}
+
+	if !slices.Equal(values, []int{1, 3}) {
+		t.Errorf("Expected values [1 3], got %v", values)
+	}
+	if len(errs) != 1 {
+		t.Errorf("Expected 1 error, got %d", len(errs))
+	}
+
+	// Stopping early must not invoke fn for later elements
+	calls := 0
+	for range MapErrSeq(slices.Values([]int{1, 2, 3}), func(n int) (int, error) {
+		calls++
+		return n, nil
+	}) {
+		break
+	}
+	if calls != 1 {
+		t.Errorf("Expected 1 call after early break, got %d", calls)
+	}
+}
+
+func TestForEachSeqCtx(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var processed []int
+	err := ForEachSeqCtx(ctx, slices.Values([]int{1, 2, 3, 4}), func(ctx context.Context, n int) error {
+		processed = append(processed, n)
+		if n == 2 {
+			cancel()
+		}
+		return nil
+	})
+	if err != context.Canceled {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+	if !slices.Equal(processed, []int{1, 2}) {
+		t.Errorf("Expected to stop after [1 2], got %v", processed)
+	}
+
+	// Without cancellation all elements are processed
+	sum := 0
+	err = ForEachSeqCtx(context.Background(), slices.Values([]int{1, 2, 3}), func(ctx context.Context, n int) error {
+		sum += n
+		return nil
+	})
+	if err != nil || sum != 6 {
+		t.Errorf("Expected sum 6 with no error, got %d, %v", sum, err)
+	}
+}
+
+func TestCollectSeqCtx(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	result, err := CollectSeqCtx(ctx, slices.Values([]int{1, 2, 3}))
+	if err != context.Canceled {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+	if len(result) != 0 {
+		t.Errorf("Expected no elements from cancelled context, got %v", result)
+	}
+
+	result, err = CollectSeqCtx(context.Background(), slices.Values([]int{1, 2, 3}))
+	if err != nil || !slices.Equal(result, []int{1, 2, 3}) {
+		t.Errorf("Expected [1 2 3] with no error, got %v, %v", result, err)
+	}
+}
+
+func TestParallelForEachSeq(t *testing.T) {
+	server := newPagingServer(40)
+	defer server.Close()
+
+	client := newFastClient(server.URL)
+	iter := client.NewQuery().SearchQuery("test").MaxResults(10).Iterator(context.Background())
+
+	var mu sync.Mutex
+	seen := make(map[string]bool)
+	err := ParallelForEachSeq(iter.All(), 4, func(paper *Paper) error {
+		mu.Lock()
+		defer mu.Unlock()
+		seen[paper.ID] = true
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("ParallelForEachSeq error: %v", err)
+	}
+	if len(seen) != 40 {
+		t.Errorf("Expected 40 distinct papers, got %d", len(seen))
+	}
+}
+
+func TestParallelForEachSeq_Error(t *testing.T) {
+	boom := errors.New("boom")
+	pulled := 0
+	seq := func(yield func(int) bool) {
+		for i := 0; i < 1000; i++ {
+			pulled++
+			if !yield(i) {
+				return
+			}
+		}
+	}
+
+	err := ParallelForEachSeq(seq, 3, func(n int) error {
+		if n == 5 {
+			return boom
+		}
+		return nil
+	})
+	if err != boom {
+		t.Errorf("Expected boom error, got %v", err)
+	}
+	if pulled >= 1000 {
+		t.Errorf("Expected upstream to stop early, pulled %d items", pulled)
+	}
+}
+
 // TestIterator_EarlyBreak tests that early breaking from iteration works correctly
+func TestIterator_Drain(t *testing.T) {
+	server := newPagingServer(25)
+	defer server.Close()
+
+	client := newFastClient(server.URL)
+	iter := client.NewQuery().SearchQuery("test").MaxResults(10).Limit(22).Iterator(context.Background())
+
+	if _, err := iter.CollectN(3); err != nil {
+		t.Fatalf("CollectN error: %v", err)
+	}
+	iter.Drain()
+
+	if err := iter.Error(); err != nil {
+		t.Fatalf("Drain error: %v", err)
+	}
+	if iter.TotalFetched() != 22 {
+		t.Errorf("Expected Drain to consume up to the limit of 22, got %d", iter.TotalFetched())
+	}
+	if papers, _ := iter.Collect(); len(papers) != 0 {
+		t.Errorf("Expected no papers after Drain, got %d", len(papers))
+	}
+}
+
+func TestIterator_CloseAfterEarlyBreak(t *testing.T) {
+	var requests atomic.Int32
+	paging := newPagingServer(30)
+	defer paging.Close()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		paging.Config.Handler.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	client := newFastClient(server.URL)
+	iter := client.NewQuery().SearchQuery("test").MaxResults(10).Iterator(context.Background())
+
+	for range iter.All() {
+		break
+	}
+	if err := iter.Close(); err != nil {
+		t.Fatalf("Close returned error: %v", err)
+	}
+	if err := iter.Close(); err != nil {
+		t.Fatalf("Second Close returned error: %v", err)
+	}
+
+	for range iter.All() {
+		t.Fatal("Expected a closed iterator to yield nothing")
+	}
+	if n := requests.Load(); n != 1 {
+		t.Errorf("Expected no requests after Close, got %d in total", n)
+	}
+}
+
 func TestIterator_EarlyBreak(t *testing.T) {
 	// Create a mock server
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -921,3 +1845,96 @@ func TestIterator_EarlyBreak(t *testing.T) {
 		t.Errorf("Expected TotalFetched to be 2 after early break, got %d", iter.TotalFetched())
 	}
 }
+
+func TestIterator_CollectConcurrent(t *testing.T) {
+	var mu sync.Mutex
+	inFlight, maxInFlight := 0, 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		inFlight++
+		maxInFlight = max(maxInFlight, inFlight)
+		mu.Unlock()
+		defer func() {
+			mu.Lock()
+			inFlight--
+			mu.Unlock()
+		}()
+
+		// Later pages answer faster, so they complete out of order
+		start, _ := strconv.Atoi(r.URL.Query().Get("start"))
+		maxResults, _ := strconv.Atoi(r.URL.Query().Get("max_results"))
+		time.Sleep(time.Duration(25-start) * time.Millisecond)
+		w.Header().Set("Content-Type", "application/xml")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(generateFeed(23, start, min(maxResults, 23-start))))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name     string
+		limit    int
+		expected int
+	}{
+		{"all", 0, 23},
+		{"limit", 12, 12},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newFastClient(server.URL)
+			iter := client.NewQuery().SearchQuery("test").MaxResults(5).Limit(tt.limit).Iterator(context.Background())
+
+			papers, err := iter.CollectConcurrent(context.Background(), 4)
+			if err != nil {
+				t.Fatalf("CollectConcurrent error: %v", err)
+			}
+			if len(papers) != tt.expected {
+				t.Fatalf("Expected %d papers, got %d", tt.expected, len(papers))
+			}
+			for i, paper := range papers {
+				if expected := fmt.Sprintf("2301.%05dv1", i); paper.ID != expected {
+					t.Errorf("Expected paper %d to be %s, got %s", i, expected, paper.ID)
+				}
+			}
+			if iter.TotalFetched() != tt.expected {
+				t.Errorf("Expected TotalFetched %d, got %d", tt.expected, iter.TotalFetched())
+			}
+			for range iter.All() {
+				t.Fatal("Expected the iterator to be exhausted")
+			}
+		})
+	}
+
+	if maxInFlight < 2 {
+		t.Errorf("Expected concurrent page requests, got at most %d in flight", maxInFlight)
+	}
+}
+
+func TestIterator_CollectConcurrentError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start, _ := strconv.Atoi(r.URL.Query().Get("start"))
+		maxResults, _ := strconv.Atoi(r.URL.Query().Get("max_results"))
+		if start == 10 {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/xml")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(generateFeed(30, start, min(maxResults, 30-start))))
+	}))
+	defer server.Close()
+
+	client := NewClientWithOptions(ClientOptions{RateLimit: time.Nanosecond, RetryAttempts: 1})
+	client.baseURL = server.URL
+	iter := client.NewQuery().SearchQuery("test").MaxResults(5).Iterator(context.Background())
+
+	papers, err := iter.CollectConcurrent(context.Background(), 3)
+	if err == nil {
+		t.Fatal("Expected the failed page to be reported")
+	}
+	if len(papers) != 10 || papers[9].ID != "2301.00009v1" {
+		t.Errorf("Expected the 10 papers before the failed page, got %d", len(papers))
+	}
+	if iter.Error() == nil || iter.TotalFetched() != 10 {
+		t.Errorf("Expected the iterator in the error state after 10 papers, got %v, %d", iter.Error(), iter.TotalFetched())
+	}
+}
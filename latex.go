@@ -0,0 +1,178 @@
+package arxiv
+
+import "strings"
+
+// latexSymbols maps LaTeX control words to their Unicode rendering
+var latexSymbols = map[string]string{
+	// Greek letters
+	"alpha": "α", "beta": "β", "gamma": "γ", "delta": "δ", "epsilon": "ε", "varepsilon": "ε",
+	"zeta": "ζ", "eta": "η", "theta": "θ", "vartheta": "ϑ", "iota": "ι", "kappa": "κ",
+	"lambda": "λ", "mu": "μ", "nu": "ν", "xi": "ξ", "pi": "π", "rho": "ρ", "sigma": "σ",
+	"tau": "τ", "upsilon": "υ", "phi": "φ", "varphi": "φ", "chi": "χ", "psi": "ψ", "omega": "ω",
+	"Gamma": "Γ", "Delta": "Δ", "Theta": "Θ", "Lambda": "Λ", "Xi": "Ξ", "Pi": "Π",
+	"Sigma": "Σ", "Upsilon": "Υ", "Phi": "Φ", "Psi": "Ψ", "Omega": "Ω",
+
+	// Math symbols
+	"infty": "∞", "pm": "±", "mp": "∓", "times": "×", "div": "÷", "cdot": "·",
+	"leq": "≤", "le": "≤", "geq": "≥", "ge": "≥", "neq": "≠", "ne": "≠", "approx": "≈",
+	"sim": "~", "simeq": "≃", "equiv": "≡", "propto": "∝", "ll": "≪", "gg": "≫",
+	"to": "→", "rightarrow": "→", "leftarrow": "←", "Rightarrow": "⇒", "leftrightarrow": "↔",
+	"in": "∈", "notin": "∉", "subset": "⊂", "subseteq": "⊆", "cup": "∪", "cap": "∩",
+	"forall": "∀", "exists": "∃", "emptyset": "∅", "partial": "∂", "nabla": "∇",
+	"sum": "∑", "prod": "∏", "int": "∫", "sqrt": "√", "ell": "ℓ", "hbar": "ℏ",
+	"ldots": "…", "dots": "…", "cdots": "⋯", "langle": "⟨", "rangle": "⟩",
+
+	// Operator names render as themselves
+	"log": "log", "ln": "ln", "exp": "exp", "sin": "sin", "cos": "cos", "tan": "tan",
+	"min": "min", "max": "max", "lim": "lim", "sup": "sup", "inf": "inf", "det": "det",
+
+	// Text symbols
+	"ss": "ß", "o": "ø", "O": "Ø", "aa": "å", "AA": "Å", "ae": "æ", "AE": "Æ",
+	"oe": "œ", "OE": "Œ", "l": "ł", "L": "Ł", "i": "ı",
+	"textendash": "–", "textemdash": "—", "S": "§", "P": "¶", "copyright": "©",
+	"quad": " ", "qquad": " ",
+}
+
+// latexAccents maps an accent command followed by a base letter to the accented
+// letter. The key is the accent ("'", "`", "^", "\"", "~", or a letter command
+// such as "c" or "v") immediately followed by the letter.
+var latexAccents = map[string]string{
+	"'a": "á", "'e": "é", "'i": "í", "'o": "ó", "'u": "ú", "'y": "ý", "'c": "ć", "'n": "ń", "'s": "ś", "'z": "ź",
+	"'A": "Á", "'E": "É", "'I": "Í", "'O": "Ó", "'U": "Ú", "'Y": "Ý", "'C": "Ć", "'N": "Ń", "'S": "Ś", "'Z": "Ź",
+	"`a": "à", "`e": "è", "`i": "ì", "`o": "ò", "`u": "ù",
+	"`A": "À", "`E": "È", "`I": "Ì", "`O": "Ò", "`U": "Ù",
+	"^a": "â", "^e": "ê", "^i": "î", "^o": "ô", "^u": "û",
+	"^A": "Â", "^E": "Ê", "^I": "Î", "^O": "Ô", "^U": "Û",
+	"\"a": "ä", "\"e": "ë", "\"i": "ï", "\"o": "ö", "\"u": "ü", "\"y": "ÿ",
+	"\"A": "Ä", "\"E": "Ë", "\"I": "Ï", "\"O": "Ö", "\"U": "Ü",
+	"~a": "ã", "~n": "ñ", "~o": "õ", "~A": "Ã", "~N": "Ñ", "~O": "Õ",
+	"cc": "ç", "cC": "Ç", "cs": "ş", "cS": "Ş",
+	"vc": "č", "vs": "š", "vz": "ž", "vr": "ř", "ve": "ě", "vn": "ň",
+	"vC": "Č", "vS": "Š", "vZ": "Ž", "vR": "Ř", "vE": "Ě", "vN": "Ň",
+	"Ho": "ő", "Hu": "ű", "HO": "Ő", "HU": "Ű",
+	"ua": "ă", "ug": "ğ", "uA": "Ă", "uG": "Ğ",
+	"ra": "å", "rA": "Å",
+}
+
+// latexLetterAccents lists the accent commands spelled as letters, e.g. \c{c}
+const latexLetterAccents = "cvHur"
+
+// latexToPlain renders LaTeX markup as readable plain text. Math delimiters and
+// grouping braces are dropped, common symbols and accents become Unicode, and
+// formatting commands such as \emph{...} keep only their content. Unknown
+// commands are dropped. The result has its whitespace collapsed.
+func latexToPlain(s string) string {
+	var b strings.Builder
+	b.Grow(len(s))
+
+	for i := 0; i < len(s); i++ {
+		switch ch := s[i]; ch {
+		case '\\':
+			i = writeLatexCommand(&b, s, i)
+		case '$', '{', '}':
+			// Math delimiters and grouping braces carry no text
+		case '~':
+			b.WriteByte(' ')
+		case '-':
+			switch {
+			case strings.HasPrefix(s[i:], "---"):
+				b.WriteString("—")
+				i += 2
+			case strings.HasPrefix(s[i:], "--"):
+				b.WriteString("–")
+				i++
+			default:
+				b.WriteByte(ch)
+			}
+		case '`', '\'':
+			if i+1 < len(s) && s[i+1] == ch {
+				if ch == '`' {
+					b.WriteString("“")
+				} else {
+					b.WriteString("”")
+				}
+				i++
+			} else {
+				b.WriteByte(ch)
+			}
+		default:
+			b.WriteByte(ch)
+		}
+	}
+	return collapseWhitespace(b.String())
+}
+
+// writeLatexCommand renders the command starting at the backslash s[i] and
+// returns the index of its last consumed byte
+func writeLatexCommand(b *strings.Builder, s string, i int) int {
+	if i+1 >= len(s) {
+		return i
+	}
+	next := s[i+1]
+
+	// Control word, e.g. \alpha or \emph
+	if isASCIILetter(next) {
+		end := i + 1
+		for end < len(s) && isASCIILetter(s[end]) {
+			end++
+		}
+		name := s[i+1 : end]
+		if len(name) == 1 && end < len(s) && s[end] == '{' && strings.Contains(latexLetterAccents, name) {
+			return writeLatexAccent(b, s, name, end)
+		}
+		if symbol, ok := latexSymbols[name]; ok {
+			b.WriteString(symbol)
+		}
+		return end - 1
+	}
+
+	// Control symbol, e.g. \% or \'
+	switch next {
+	case '\'', '`', '^', '"', '~':
+		return writeLatexAccent(b, s, string(next), i+2)
+	case '%', '&', '$', '_', '#', '{', '}':
+		b.WriteByte(next)
+	case '\\', ',', ';', ' ':
+		b.WriteByte(' ')
+	}
+	// Anything else, including \( \) \[ \] and \!, is dropped
+	return i + 1
+}
+
+// writeLatexAccent renders accent applied to the argument starting at s[i],
+// either a braced group or a single letter, and returns the index of its last
+// consumed byte. Unknown combinations keep the bare argument.
+func writeLatexAccent(b *strings.Builder, s, accent string, i int) int {
+	if i >= len(s) {
+		return i - 1
+	}
+
+	var arg string
+	last := i
+	if s[i] == '{' {
+		end := strings.IndexByte(s[i:], '}')
+		if end < 0 {
+			return i - 1
+		}
+		arg = s[i+1 : i+end]
+		last = i + end
+	} else if isASCIILetter(s[i]) {
+		arg = s[i : i+1]
+	} else {
+		return i - 1
+	}
+
+	// Accents over a dotless i are written \'{\i}
+	arg = strings.TrimSpace(strings.TrimPrefix(arg, "\\"))
+	if accented, ok := latexAccents[accent+arg]; ok {
+		b.WriteString(accented)
+	} else {
+		b.WriteString(arg)
+	}
+	return last
+}
+
+// isASCIILetter reports whether ch is an ASCII letter, the characters of a control word
+func isASCIILetter(ch byte) bool {
+	return ch >= 'a' && ch <= 'z' || ch >= 'A' && ch <= 'Z'
+}
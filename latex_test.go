@@ -0,0 +1,48 @@
+package arxiv
+
+import "testing"
+
+func TestLatexToPlain(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"plain text", "We study quantum systems.", "We study quantum systems."},
+		{"inline math", `A $\alpha$-stable process with $O(n^2)$ cost`, "A α-stable process with O(n^2) cost"},
+		{"display math", `the bound $$\sum_{i} x_i \leq \infty$$ holds`, "the bound ∑_i x_i ≤ ∞ holds"},
+		{"paren math", `where \(\epsilon \to 0\) and \[\Delta \neq 0\]`, "where ε → 0 and Δ ≠ 0"},
+		{"formatting", `\emph{very} \textbf{bold} and \texttt{code}`, "very bold and code"},
+		{"accents", `Schr\"odinger, na\"ive, Erd\H{o}s, \c{c}a, G\"{o}del`, "Schrödinger, naïve, Erdős, ça, Gödel"},
+		{"dotless i", `Mart\'{\i}n`, "Martín"},
+		{"text symbols", `Stra\ss{}e and \O{}rsted`, "Straße and Ørsted"},
+		{"escapes", `50\% of \$10 \& more`, "50% of $10 & more"},
+		{"dashes and quotes", "pages 1--10 --- ``quoted'' text", "pages 1–10 — “quoted” text"},
+		{"tie and spacing", `Fig.~1 and a\,b`, "Fig. 1 and a b"},
+		{"unknown command", `see \cite{smith} for details`, "see smith for details"},
+		{"newlines", "first line\n  second line", "first line second line"},
+		{"trailing backslash", `ends with \`, "ends with"},
+		{"unterminated accent", `broken \'{e`, "broken e"},
+		{"multibyte after accent", `\'é stays`, "é stays"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := latexToPlain(tt.input); got != tt.expected {
+				t.Errorf("latexToPlain(%q): expected %q, got %q", tt.input, tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestPaper_PlainAbstract(t *testing.T) {
+	raw := `We show that $\mathcal{O}(\log n)$ queries \emph{suffice}.`
+	paper := &Paper{Abstract: raw}
+
+	if got := paper.PlainAbstract(); got != "We show that O(log n) queries suffice." {
+		t.Errorf("Unexpected plain abstract: %q", got)
+	}
+	if paper.Abstract != raw {
+		t.Errorf("Expected raw abstract to be untouched, got %q", paper.Abstract)
+	}
+}
@@ -0,0 +1,275 @@
+package arxiv
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"iter"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	// arXiv OAI-PMH base URL
+	oaiBaseURL = "http://export.arxiv.org/oai2"
+
+	// Default metadata format, arXiv's own schema with structured author names
+	defaultOAIMetadataPrefix = "arXiv"
+
+	// Date format used by OAI-PMH selectors and arXiv metadata
+	oaiDateFormat = "2006-01-02"
+
+	// OAI-PMH error code for a harvest that matches nothing
+	oaiNoRecordsMatch = "noRecordsMatch"
+)
+
+// OAIClient harvests bulk metadata from arXiv's OAI-PMH interface.
+// It shares retry, rate limiting and User-Agent handling with Client.
+type OAIClient struct {
+	client  *Client
+	baseURL string
+}
+
+// OAIListRequest selects the records returned by ListRecords
+type OAIListRequest struct {
+	// Set restricts the harvest to an arXiv set, e.g. "cs" or "physics:hep-th"
+	Set string
+
+	// From and Until restrict the harvest by datestamp (inclusive)
+	From  *time.Time
+	Until *time.Time
+
+	// ResumptionToken continues a previous harvest; the other selectors are ignored when set
+	ResumptionToken string
+}
+
+// NewOAIClient creates a new OAI-PMH client with default options
+func NewOAIClient() *OAIClient {
+	return NewOAIClientWithOptions(DefaultClientOptions())
+}
+
+// NewOAIClientWithOptions creates a new OAI-PMH client with custom options
+func NewOAIClientWithOptions(opts ClientOptions) *OAIClient {
+	return &OAIClient{
+		client:  NewClientWithOptions(opts),
+		baseURL: oaiBaseURL,
+	}
+}
+
+// XML structures for parsing OAI-PMH ListRecords responses
+type oaiResponse struct {
+	XMLName xml.Name `xml:"http://www.openarchives.org/OAI/2.0/ OAI-PMH"`
+	Error   *struct {
+		Code    string `xml:"code,attr"`
+		Message string `xml:",chardata"`
+	} `xml:"error"`
+	ListRecords struct {
+		Records         []oaiRecord `xml:"record"`
+		ResumptionToken struct {
+			Token            string `xml:",chardata"`
+			Cursor           int    `xml:"cursor,attr"`
+			CompleteListSize int    `xml:"completeListSize,attr"`
+		} `xml:"resumptionToken"`
+	} `xml:"ListRecords"`
+}
+
+type oaiRecord struct {
+	Header struct {
+		Status string `xml:"status,attr"`
+	} `xml:"header"`
+	Metadata struct {
+		ArXiv *oaiArxivMetadata `xml:"http://arxiv.org/OAI/arXiv/ arXiv"`
+	} `xml:"metadata"`
+}
+
+type oaiArxivMetadata struct {
+	ID      string `xml:"id"`
+	Created string `xml:"created"`
+	Updated string `xml:"updated"`
+	Authors []struct {
+		KeyName     string `xml:"keyname"`
+		ForeNames   string `xml:"forenames"`
+		Suffix      string `xml:"suffix"`
+		Affiliation string `xml:"affiliation"`
+	} `xml:"authors>author"`
+	Title      string `xml:"title"`
+	Categories string `xml:"categories"`
+	Comments   string `xml:"comments"`
+	JournalRef string `xml:"journal-ref"`
+	DOI        string `xml:"doi"`
+	ACMClass   string `xml:"acm-class"`
+	MSCClass   string `xml:"msc-class"`
+	Abstract   string `xml:"abstract"`
+}
+
+// ListRecords harvests a single page of records. The returned resumption token is
+// empty once the harvest is complete; pass it back in req to fetch the next page.
+func (c *OAIClient) ListRecords(ctx context.Context, req OAIListRequest) (*SearchResults, string, error) {
+	reqURL := fmt.Sprintf("%s?%s", c.baseURL, buildOAIParams(req).Encode())
+
+	var result *SearchResults
+	var token string
+	err := c.client.retryWithBackoff(ctx, func() error {
+		return c.client.get(ctx, reqURL, 0, func(body []byte) error {
+			parsedResult, parsedToken, err := parseOAIResponse(body, !c.client.options.PreserveWhitespace)
+			if err != nil {
+				return err
+			}
+			result, token = parsedResult, parsedToken
+			return nil
+		})
+	})
+
+	if err != nil {
+		return nil, "", err
+	}
+	return result, token, nil
+}
+
+// Records returns an iterator over every record in the harvest, following resumption tokens.
+// Iteration stops after yielding the first error.
+func (c *OAIClient) Records(ctx context.Context, req OAIListRequest) iter.Seq2[*Paper, error] {
+	return func(yield func(*Paper, error) bool) {
+		for {
+			results, token, err := c.ListRecords(ctx, req)
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+
+			for i := range results.Papers {
+				if !yield(&results.Papers[i], nil) {
+					return
+				}
+			}
+
+			if token == "" {
+				return
+			}
+			req = OAIListRequest{ResumptionToken: token}
+		}
+	}
+}
+
+// buildOAIParams builds the ListRecords URL query parameters
+func buildOAIParams(req OAIListRequest) url.Values {
+	params := url.Values{}
+	params.Set("verb", "ListRecords")
+
+	// A resumption token is exclusive with every other argument
+	if req.ResumptionToken != "" {
+		params.Set("resumptionToken", req.ResumptionToken)
+		return params
+	}
+
+	params.Set("metadataPrefix", defaultOAIMetadataPrefix)
+	if req.Set != "" {
+		params.Set("set", req.Set)
+	}
+	if req.From != nil {
+		params.Set("from", req.From.Format(oaiDateFormat))
+	}
+	if req.Until != nil {
+		params.Set("until", req.Until.Format(oaiDateFormat))
+	}
+	return params
+}
+
+// parseOAIResponse parses a ListRecords response into results and the next resumption token.
+// normalizeWhitespace collapses whitespace in titles and abstracts, as for the Atom API.
+func parseOAIResponse(data []byte, normalizeWhitespace bool) (*SearchResults, string, error) {
+	var resp oaiResponse
+	if err := unmarshalXML(data, &resp); err != nil {
+		return nil, "", newParseError("failed to parse OAI-PMH response", err)
+	}
+
+	if resp.Error != nil {
+		if resp.Error.Code == oaiNoRecordsMatch {
+			return &SearchResults{Papers: []Paper{}}, "", nil
+		}
+		return nil, "", NewAPIError(ErrorTypeInvalidQuery, fmt.Sprintf("OAI-PMH error %s: %s", resp.Error.Code, strings.TrimSpace(resp.Error.Message)), nil)
+	}
+
+	papers := make([]Paper, 0, len(resp.ListRecords.Records))
+	for i, record := range resp.ListRecords.Records {
+		// Deleted records carry no metadata
+		if record.Header.Status == "deleted" || record.Metadata.ArXiv == nil {
+			continue
+		}
+		paper, err := convertOAIMetadataToPaper(record.Metadata.ArXiv, normalizeWhitespace)
+		if err != nil {
+			return nil, "", NewAPIError(ErrorTypeParsing, fmt.Sprintf("failed to convert record %d", i), err)
+		}
+		papers = append(papers, *paper)
+	}
+
+	token := resp.ListRecords.ResumptionToken
+	return &SearchResults{
+		Papers:       papers,
+		TotalCount:   token.CompleteListSize,
+		StartIndex:   token.Cursor,
+		ItemsPerPage: len(papers),
+	}, strings.TrimSpace(token.Token), nil
+}
+
+// convertOAIMetadataToPaper converts arXiv OAI metadata to a Paper struct
+func convertOAIMetadataToPaper(meta *oaiArxivMetadata, normalizeWhitespace bool) (*Paper, error) {
+	publishedAt, err := time.Parse(oaiDateFormat, meta.Created)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse created date: %w", err)
+	}
+
+	updatedAt := publishedAt
+	if meta.Updated != "" {
+		updatedAt, err = time.Parse(oaiDateFormat, meta.Updated)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse updated date: %w", err)
+		}
+	}
+
+	authors := make([]Author, len(meta.Authors))
+	for i, author := range meta.Authors {
+		name := strings.TrimSpace(author.ForeNames + " " + author.KeyName + " " + author.Suffix)
+		authors[i] = Author{
+			Name:        name,
+			Affiliation: strings.TrimSpace(author.Affiliation),
+		}
+	}
+
+	title := strings.TrimSpace(meta.Title)
+	abstract := strings.TrimSpace(meta.Abstract)
+	if normalizeWhitespace {
+		title = collapseWhitespace(title)
+		abstract = collapseWhitespace(abstract)
+	}
+
+	// The arXiv metadata format has no primary category element; its categories field
+	// lists the primary category first
+	categories := strings.Fields(meta.Categories)
+	var primaryCategory string
+	if len(categories) > 0 {
+		primaryCategory = categories[0]
+	}
+
+	id := strings.TrimSpace(meta.ID)
+	return &Paper{
+		ID:          id,
+		Title:       title,
+		Abstract:    abstract,
+		Authors:     authors,
+		Categories:  categories,
+		PublishedAt: publishedAt,
+		UpdatedAt:   updatedAt,
+		DOI:         strings.TrimSpace(meta.DOI),
+		JournalRef:  strings.TrimSpace(meta.JournalRef),
+		Comment:     strings.TrimSpace(meta.Comments),
+		ACMClass:    strings.TrimSpace(meta.ACMClass),
+		MSCClass:    strings.TrimSpace(meta.MSCClass),
+		Links: []Link{
+			{Href: absURLPrefix + id, Rel: "alternate", Type: "text/html"},
+		},
+
+		primaryCategory: primaryCategory,
+	}, nil
+}
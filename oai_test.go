@@ -0,0 +1,230 @@
+package arxiv
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// oaiPage builds a ListRecords response with the given records XML and resumption token
+func oaiPage(records, token string, cursor int) string {
+	return fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<OAI-PMH xmlns="http://www.openarchives.org/OAI/2.0/">
+  <responseDate>2023-01-05T00:00:00Z</responseDate>
+  <request verb="ListRecords">http://export.arxiv.org/oai2</request>
+  <ListRecords>%s
+    <resumptionToken cursor="%d" completeListSize="3">%s</resumptionToken>
+  </ListRecords>
+</OAI-PMH>`, records, cursor, token)
+}
+
+const oaiRecordsPage1 = `
+    <record>
+      <header>
+        <identifier>oai:arXiv.org:0704.0001</identifier>
+        <datestamp>2008-11-13</datestamp>
+        <setSpec>physics:hep-ph</setSpec>
+      </header>
+      <metadata>
+        <arXiv xmlns="http://arxiv.org/OAI/arXiv/">
+          <id>0704.0001</id>
+          <created>2007-04-02</created>
+          <updated>2008-11-13</updated>
+          <authors>
+            <author><keyname>Balázs</keyname><forenames>C.</forenames><affiliation>Argonne</affiliation></author>
+            <author><keyname>Berger</keyname><forenames>E. L.</forenames></author>
+          </authors>
+          <title>Calculation of prompt diphoton production cross sections</title>
+          <categories>hep-ph hep-ex</categories>
+          <comments>37 pages, 15 figures</comments>
+          <journal-ref>Phys.Rev.D76:013009,2007</journal-ref>
+          <doi>10.1103/PhysRevD.76.013009</doi>
+          <abstract>  A fully differential calculation.  </abstract>
+        </arXiv>
+      </metadata>
+    </record>
+    <record>
+      <header status="deleted">
+        <identifier>oai:arXiv.org:0704.0002</identifier>
+        <datestamp>2008-11-13</datestamp>
+      </header>
+    </record>`
+
+const oaiRecordsPage2 = `
+    <record>
+      <header>
+        <identifier>oai:arXiv.org:0704.0003</identifier>
+        <datestamp>2008-11-13</datestamp>
+      </header>
+      <metadata>
+        <arXiv xmlns="http://arxiv.org/OAI/arXiv/">
+          <id>0704.0003</id>
+          <created>2007-04-01</created>
+          <authors><author><keyname>Pan</keyname><forenames>Hongjun</forenames></author></authors>
+          <title>The evolution of the Earth-Moon system</title>
+          <categories>physics.gen-ph</categories>
+          <abstract>Abstract three.</abstract>
+        </arXiv>
+      </metadata>
+    </record>`
+
+func TestOAIClient_Records(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.URL.RawQuery)
+		w.Header().Set("Content-Type", "text/xml")
+		w.WriteHeader(http.StatusOK)
+
+		if token := r.URL.Query().Get("resumptionToken"); token != "" {
+			if token != "6960524|1001" {
+				t.Errorf("Unexpected resumption token '%s'", token)
+			}
+			w.Write([]byte(oaiPage(oaiRecordsPage2, "", 2)))
+			return
+		}
+
+		params := r.URL.Query()
+		if params.Get("verb") != "ListRecords" || params.Get("metadataPrefix") != "arXiv" {
+			t.Errorf("Unexpected OAI parameters: %s", r.URL.RawQuery)
+		}
+		if params.Get("set") != "physics:hep-ph" || params.Get("from") != "2008-11-01" || params.Get("until") != "2008-11-30" {
+			t.Errorf("Unexpected selectors: %s", r.URL.RawQuery)
+		}
+		w.Write([]byte(oaiPage(oaiRecordsPage1, "6960524|1001", 0)))
+	}))
+	defer server.Close()
+
+	client := NewOAIClientWithOptions(ClientOptions{RateLimit: time.Nanosecond})
+	client.baseURL = server.URL
+
+	from := time.Date(2008, 11, 1, 0, 0, 0, 0, time.UTC)
+	until := time.Date(2008, 11, 30, 0, 0, 0, 0, time.UTC)
+	req := OAIListRequest{Set: "physics:hep-ph", From: &from, Until: &until}
+
+	var papers []*Paper
+	for paper, err := range client.Records(context.Background(), req) {
+		if err != nil {
+			t.Fatalf("Records error: %v", err)
+		}
+		papers = append(papers, paper)
+	}
+
+	if len(requests) != 2 {
+		t.Errorf("Expected 2 requests, got %d", len(requests))
+	}
+	if len(papers) != 2 {
+		t.Fatalf("Expected 2 papers (deleted record skipped), got %d", len(papers))
+	}
+
+	paper := papers[0]
+	if paper.ID != "0704.0001" {
+		t.Errorf("Expected ID '0704.0001', got '%s'", paper.ID)
+	}
+	if len(paper.Authors) != 2 || paper.Authors[0].Name != "C. Balázs" || paper.Authors[0].Affiliation != "Argonne" {
+		t.Errorf("Unexpected authors: %+v", paper.Authors)
+	}
+	if len(paper.Categories) != 2 || paper.Categories[0] != "hep-ph" {
+		t.Errorf("Unexpected categories: %v", paper.Categories)
+	}
+	if paper.primaryCategory != "hep-ph" {
+		t.Errorf("Expected primary category 'hep-ph', got '%s'", paper.primaryCategory)
+	}
+	if !paper.PublishedAt.Equal(time.Date(2007, 4, 2, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Unexpected published date: %v", paper.PublishedAt)
+	}
+	if !paper.UpdatedAt.Equal(time.Date(2008, 11, 13, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Unexpected updated date: %v", paper.UpdatedAt)
+	}
+	if paper.Abstract != "A fully differential calculation." {
+		t.Errorf("Unexpected abstract: '%s'", paper.Abstract)
+	}
+	if paper.DOI != "10.1103/PhysRevD.76.013009" || paper.Comment != "37 pages, 15 figures" {
+		t.Errorf("Unexpected DOI/comment: '%s' '%s'", paper.DOI, paper.Comment)
+	}
+
+	if !papers[1].UpdatedAt.Equal(papers[1].PublishedAt) {
+		t.Errorf("Expected missing updated date to default to created date")
+	}
+}
+
+func TestOAIClient_PreserveWhitespace(t *testing.T) {
+	const record = `
+    <record>
+      <header><identifier>oai:arXiv.org:0704.0004</identifier><datestamp>2008-11-13</datestamp></header>
+      <metadata>
+        <arXiv xmlns="http://arxiv.org/OAI/arXiv/">
+          <id>0704.0004</id>
+          <created>2007-04-01</created>
+          <title>A determinant of Stirling
+  cycle numbers</title>
+          <abstract>  We show that a
+determinant counts   unlabeled trees.
+</abstract>
+        </arXiv>
+      </metadata>
+    </record>`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(oaiPage(record, "", 0)))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name     string
+		preserve bool
+		title    string
+		abstract string
+	}{
+		{"normalized", false, "A determinant of Stirling cycle numbers", "We show that a determinant counts unlabeled trees."},
+		{"preserved", true, "A determinant of Stirling\n  cycle numbers", "We show that a\ndeterminant counts   unlabeled trees."},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := NewOAIClientWithOptions(ClientOptions{RateLimit: time.Nanosecond, PreserveWhitespace: tt.preserve})
+			client.baseURL = server.URL
+
+			results, _, err := client.ListRecords(context.Background(), OAIListRequest{Set: "math"})
+			if err != nil {
+				t.Fatalf("ListRecords failed: %v", err)
+			}
+			if len(results.Papers) != 1 {
+				t.Fatalf("Expected 1 paper, got %d", len(results.Papers))
+			}
+			if paper := results.Papers[0]; paper.Title != tt.title || paper.Abstract != tt.abstract {
+				t.Errorf("Expected %q / %q, got %q / %q", tt.title, tt.abstract, paper.Title, paper.Abstract)
+			}
+		})
+	}
+}
+
+func TestOAIClient_ListRecordsErrors(t *testing.T) {
+	code := "noRecordsMatch"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/xml")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintf(w, `<?xml version="1.0" encoding="UTF-8"?>
+<OAI-PMH xmlns="http://www.openarchives.org/OAI/2.0/">
+  <error code="%s">explanation</error>
+</OAI-PMH>`, code)
+	}))
+	defer server.Close()
+
+	client := NewOAIClientWithOptions(ClientOptions{RateLimit: time.Nanosecond})
+	client.baseURL = server.URL
+
+	results, token, err := client.ListRecords(context.Background(), OAIListRequest{Set: "cs"})
+	if err != nil {
+		t.Fatalf("Expected noRecordsMatch to be an empty result, got %v", err)
+	}
+	if len(results.Papers) != 0 || token != "" {
+		t.Errorf("Expected empty results and no token, got %d papers, token '%s'", len(results.Papers), token)
+	}
+
+	code = "badResumptionToken"
+	_, _, err = client.ListRecords(context.Background(), OAIListRequest{ResumptionToken: "stale"})
+	if !IsInvalidQuery(err) {
+		t.Errorf("Expected invalid query error for badResumptionToken, got %v", err)
+	}
+}
@@ -0,0 +1,227 @@
+package arxiv
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// revisionTolerance is how much later than PublishedAt UpdatedAt must be for WasRevised
+const revisionTolerance = time.Minute
+
+var (
+	// Comment patterns such as "12 pages, 5 figures" or "10pp, 3 figs"
+	pageCountPattern   = regexp.MustCompile(`(?i)(\d+)\s*(?:pages?|pp)\b`)
+	figureCountPattern = regexp.MustCompile(`(?i)(\d+)\s*(?:figures?|figs?)\b`)
+
+	// Year and month part of a new-style arXiv ID, e.g. "2301"
+	yearMonthPattern = regexp.MustCompile(`^\d{2}(?:0[1-9]|1[0-2])$`)
+
+	// Trailing version suffix of an arXiv ID, e.g. "v2" in "1234.5678v2"
+	versionSuffixPattern = regexp.MustCompile(`v\d+$`)
+
+	// Standard withdrawal notices, e.g. "This paper has been withdrawn by the author"
+	// or a comment starting with "Withdrawn"
+	withdrawnPattern = regexp.MustCompile(`(?i)\b(?:paper|article|manuscript|submission)\s+(?:has\s+been|is)\s+withdrawn\b|^\s*withdrawn\b`)
+)
+
+// BaseID returns id without its version suffix: "1234.5678v2" -> "1234.5678"
+func BaseID(id string) string {
+	return versionSuffixPattern.ReplaceAllString(id, "")
+}
+
+// Key returns the paper's version-less ID, a stable key for maps and deduplication
+func (p *Paper) Key() string {
+	return BaseID(p.ID)
+}
+
+// Clone returns a deep copy of the paper whose Authors, Categories and Links can be
+// modified without affecting p. A nil paper clones to nil.
+func (p *Paper) Clone() *Paper {
+	if p == nil {
+		return nil
+	}
+	clone := *p
+	clone.Authors = slices.Clone(p.Authors)
+	clone.Categories = slices.Clone(p.Categories)
+	clone.Links = slices.Clone(p.Links)
+	return &clone
+}
+
+// Equal reports whether p and other are versions of the same paper.
+// Two nil papers are equal; a nil paper is not equal to a non-nil one.
+func (p *Paper) Equal(other *Paper) bool {
+	if p == nil || other == nil {
+		return p == other
+	}
+	return p.Key() == other.Key()
+}
+
+// IDsInRange returns the new-style arXiv IDs with sequence numbers from to to (inclusive)
+// for the month yearMonth in YYMM form, e.g. IDsInRange("2301", 1, 3) returns
+// ["2301.00001" "2301.00002" "2301.00003"]. IDs before 1501 use four-digit sequence numbers.
+// arXiv search can't match ID prefixes, so pass the result to QueryBuilder.IDList or
+// Client.GetByIDs instead; unassigned IDs are simply missing from the results.
+// It returns nil if yearMonth isn't a valid month since 0704 or the range is empty.
+func IDsInRange(yearMonth string, from, to int) []string {
+	if !yearMonthPattern.MatchString(yearMonth) || yearMonth < "0704" {
+		return nil
+	}
+
+	format := "%s.%05d"
+	maxSeq := 99999
+	if yearMonth < "1501" {
+		format = "%s.%04d"
+		maxSeq = 9999
+	}
+	from = max(from, 1)
+	to = min(to, maxSeq)
+	if from > to {
+		return nil
+	}
+
+	ids := make([]string, 0, to-from+1)
+	for seq := from; seq <= to; seq++ {
+		ids = append(ids, fmt.Sprintf(format, yearMonth, seq))
+	}
+	return ids
+}
+
+// PageCount returns the number of pages stated in the paper's comment, e.g. "12 pages"
+func (p *Paper) PageCount() (int, bool) {
+	return matchCount(pageCountPattern, p.Comment)
+}
+
+// FigureCount returns the number of figures stated in the paper's comment, e.g. "5 figures"
+func (p *Paper) FigureCount() (int, bool) {
+	return matchCount(figureCountPattern, p.Comment)
+}
+
+// HasCategory reports whether the paper is listed in cat, ignoring case.
+// An archive-level category matches all of its subject classes, so a paper
+// in "astro-ph.CO" matches both "astro-ph.CO" and "astro-ph".
+func (p *Paper) HasCategory(cat Category) bool {
+	want := strings.ToLower(string(cat))
+	if want == "" {
+		return false
+	}
+	for _, c := range p.Categories {
+		c = strings.ToLower(c)
+		if c == want || strings.HasPrefix(c, want+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// PrimaryCategory returns the paper's primary category, or "" if it has none. Papers
+// parsed from the API carry the primary category given by the feed; for others, such as
+// papers built by hand or decoded from JSON, it falls back to the first of Categories,
+// where arXiv lists the primary category.
+func (p *Paper) PrimaryCategory() string {
+	if p.primaryCategory != "" {
+		return p.primaryCategory
+	}
+	if len(p.Categories) == 0 {
+		return ""
+	}
+	return p.Categories[0]
+}
+
+// IsCrossListed reports whether the paper is listed in any category besides its primary
+// one, wherever Categories lists the primary category
+func (p *Paper) IsCrossListed() bool {
+	primary := p.PrimaryCategory()
+	for _, c := range p.Categories {
+		if !strings.EqualFold(c, primary) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsCrossListedIn reports whether the paper is listed in cat without it being the primary
+// category, matching archive-level categories like HasCategory. Use it to keep only the
+// cross-lists among the results of QueryBuilder.CrossListedIn.
+func (p *Paper) IsCrossListedIn(cat Category) bool {
+	primary := &Paper{Categories: []string{p.PrimaryCategory()}}
+	return p.HasCategory(cat) && !primary.HasCategory(cat)
+}
+
+// RelevanceRank returns the paper's 0-based position in the full result set of a
+// relevance-sorted search, or -1 if the paper didn't come from one. arXiv exposes
+// no numeric score, so the rank is the only relevance signal available.
+func (p *Paper) RelevanceRank() int {
+	return p.relevanceRank - 1
+}
+
+// PlainAbstract returns the abstract with its LaTeX markup rendered as plain text,
+// e.g. "$\alpha$-stable \emph{very} na\"ive" -> "α-stable very naïve". The conversion
+// is best-effort and covers common symbols, accents and formatting commands; the raw
+// Abstract is left untouched.
+func (p *Paper) PlainAbstract() string {
+	return latexToPlain(p.Abstract)
+}
+
+// IsWithdrawn reports whether the paper's comment or abstract carries a withdrawal notice.
+// arXiv has no structured withdrawn flag, so this is a heuristic: it only matches the
+// standard phrasing and may miss withdrawals worded differently.
+func (p *Paper) IsWithdrawn() bool {
+	return withdrawnPattern.MatchString(p.Comment) || withdrawnPattern.MatchString(p.Abstract)
+}
+
+// WasRevised reports whether the paper was updated after its first submission, i.e.
+// UpdatedAt is more than a minute after PublishedAt. It works without a version suffix
+// on the ID; papers with only a v1 have equal timestamps.
+func (p *Paper) WasRevised() bool {
+	return p.UpdatedAt.Sub(p.PublishedAt) > revisionTolerance
+}
+
+// DaysSincePublished returns the number of whole days since the paper was first
+// submitted, or -1 if PublishedAt is unset
+func (p *Paper) DaysSincePublished() int {
+	if p.PublishedAt.IsZero() {
+		return -1
+	}
+	return int(time.Since(p.PublishedAt) / (24 * time.Hour))
+}
+
+// RelatedLinks returns the paper's links with rel="related", such as the PDF link and,
+// for published papers, the DOI link titled "doi"
+func (p *Paper) RelatedLinks() []Link {
+	var related []Link
+	for _, link := range p.Links {
+		if link.Rel == "related" {
+			related = append(related, link)
+		}
+	}
+	return related
+}
+
+// DOILink returns the related link arXiv adds for papers with a DOI, e.g.
+// "http://dx.doi.org/10.1103/PhysRevD.76.013009". It reports false if the entry has none,
+// even when the DOI field is set.
+func (p *Paper) DOILink() (Link, bool) {
+	for _, link := range p.RelatedLinks() {
+		if strings.EqualFold(link.Title, "doi") {
+			return link, true
+		}
+	}
+	return Link{}, false
+}
+
+// matchCount returns the number captured by the first match of pattern in text
+func matchCount(pattern *regexp.Regexp, text string) (int, bool) {
+	match := pattern.FindStringSubmatch(text)
+	if match == nil {
+		return 0, false
+	}
+	n, err := strconv.Atoi(match[1])
+	if err != nil {
+		return 0, false
+	}
+	return n, true
+}
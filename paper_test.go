@@ -0,0 +1,363 @@
+package arxiv
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestPaper_PageAndFigureCount(t *testing.T) {
+	tests := []struct {
+		comment   string
+		pages     int
+		hasPages  bool
+		figures   int
+		hasFigure bool
+	}{
+		{"12 pages, 5 figures", 12, true, 5, true},
+		{"Accepted at NeurIPS 2023. 9 pages, 1 figure", 9, true, 1, true},
+		{"10pp, 3 figs", 10, true, 3, true},
+		{"1 Page", 1, true, 0, false},
+		{"v2: fixed typos in 3 tables", 0, false, 0, false},
+		{"", 0, false, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.comment, func(t *testing.T) {
+			paper := &Paper{Comment: tt.comment}
+
+			pages, ok := paper.PageCount()
+			if pages != tt.pages || ok != tt.hasPages {
+				t.Errorf("PageCount: expected (%d, %v), got (%d, %v)", tt.pages, tt.hasPages, pages, ok)
+			}
+
+			figures, ok := paper.FigureCount()
+			if figures != tt.figures || ok != tt.hasFigure {
+				t.Errorf("FigureCount: expected (%d, %v), got (%d, %v)", tt.figures, tt.hasFigure, figures, ok)
+			}
+		})
+	}
+}
+
+func TestBaseID(t *testing.T) {
+	tests := map[string]string{
+		"1234.5678v2":        "1234.5678",
+		"1234.5678":          "1234.5678",
+		"2301.00001v12":      "2301.00001",
+		"quant-ph/0301001v1": "quant-ph/0301001",
+		"quant-ph/0301001":   "quant-ph/0301001",
+	}
+
+	for input, expected := range tests {
+		if got := BaseID(input); got != expected {
+			t.Errorf("BaseID(%q): expected %q, got %q", input, expected, got)
+		}
+	}
+}
+
+func TestIDsInRange(t *testing.T) {
+	tests := []struct {
+		yearMonth string
+		from, to  int
+		expected  []string
+	}{
+		{"2301", 1, 3, []string{"2301.00001", "2301.00002", "2301.00003"}},
+		{"2301", 99998, 100005, []string{"2301.99998", "2301.99999"}},
+		{"1412", 9998, 10001, []string{"1412.9998", "1412.9999"}},
+		{"0704", 0, 1, []string{"0704.0001"}},
+		{"0703", 1, 2, nil},
+		{"2313", 1, 2, nil},
+		{"23+1", 1, 2, nil},
+		{"2301", 5, 4, nil},
+	}
+
+	for _, tt := range tests {
+		if got := IDsInRange(tt.yearMonth, tt.from, tt.to); !slices.Equal(got, tt.expected) {
+			t.Errorf("IDsInRange(%q, %d, %d): expected %v, got %v", tt.yearMonth, tt.from, tt.to, tt.expected, got)
+		}
+	}
+}
+
+func TestPaper_KeyAndEqual(t *testing.T) {
+	tests := []struct {
+		a, b  string
+		key   string
+		equal bool
+	}{
+		{"1234.5678v2", "1234.5678v1", "1234.5678", true},
+		{"1234.5678", "1234.5678v3", "1234.5678", true},
+		{"1234.5678v1", "1234.5679v1", "1234.5678", false},
+		{"quant-ph/0301001v2", "quant-ph/0301001", "quant-ph/0301001", true},
+		{"quant-ph/0301001", "hep-th/0301001", "quant-ph/0301001", false},
+	}
+
+	for _, tt := range tests {
+		a, b := &Paper{ID: tt.a}, &Paper{ID: tt.b}
+		if got := a.Key(); got != tt.key {
+			t.Errorf("Key(%q): expected %q, got %q", tt.a, tt.key, got)
+		}
+		if got := a.Equal(b); got != tt.equal {
+			t.Errorf("Equal(%q, %q): expected %v, got %v", tt.a, tt.b, tt.equal, got)
+		}
+	}
+
+	var nilPaper *Paper
+	if !nilPaper.Equal(nil) || nilPaper.Equal(&Paper{}) || (&Paper{}).Equal(nil) {
+		t.Error("Expected only two nil papers to be equal")
+	}
+}
+
+func TestPaper_IsCrossListed(t *testing.T) {
+	feed := `<?xml version="1.0" encoding="UTF-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom">
+  <entry>
+    <id>http://arxiv.org/abs/2301.00001v1</id>
+    <published>2023-01-01T00:00:00Z</published>
+    <updated>2023-01-01T00:00:00Z</updated>
+    <category term="cs.LG" scheme="http://arxiv.org/schemas/atom"/>
+    <category term="stat.ML" scheme="http://arxiv.org/schemas/atom"/>
+  </entry>
+  <entry>
+    <id>http://arxiv.org/abs/2301.00002v1</id>
+    <published>2023-01-01T00:00:00Z</published>
+    <updated>2023-01-01T00:00:00Z</updated>
+    <category term="stat.ML" scheme="http://arxiv.org/schemas/atom"/>
+    <category term="cs.LG" scheme="http://arxiv.org/schemas/atom"/>
+  </entry>
+  <entry>
+    <id>http://arxiv.org/abs/2301.00003v1</id>
+    <published>2023-01-01T00:00:00Z</published>
+    <updated>2023-01-01T00:00:00Z</updated>
+    <category term="cs.AI" scheme="http://arxiv.org/schemas/atom"/>
+  </entry>
+  <entry>
+    <id>http://arxiv.org/abs/2301.00004v1</id>
+    <published>2023-01-01T00:00:00Z</published>
+    <updated>2023-01-01T00:00:00Z</updated>
+    <arxiv:primary_category xmlns:arxiv="http://arxiv.org/schemas/atom" term="cs.LG"/>
+    <category term="stat.ML" scheme="http://arxiv.org/schemas/atom"/>
+    <category term="cs.LG" scheme="http://arxiv.org/schemas/atom"/>
+  </entry>
+  <entry>
+    <id>http://arxiv.org/abs/2301.00005v1</id>
+    <published>2023-01-01T00:00:00Z</published>
+    <updated>2023-01-01T00:00:00Z</updated>
+    <arxiv:primary_category xmlns:arxiv="http://arxiv.org/schemas/atom" term="stat.ML"/>
+    <category term="cs.LG" scheme="http://arxiv.org/schemas/atom"/>
+    <category term="stat.ML" scheme="http://arxiv.org/schemas/atom"/>
+  </entry>
+</feed>`
+
+	results, err := NewClient().parseSearchResponse([]byte(feed), false)
+	if err != nil {
+		t.Fatalf("parseSearchResponse failed: %v", err)
+	}
+
+	// The last two entries list their primary category second
+	tests := []struct {
+		crossListed   bool
+		inStatML      bool
+		inStatArchive bool
+	}{
+		{true, true, true},
+		{true, false, false},
+		{false, false, false},
+		{true, true, true},
+		{true, false, false},
+	}
+	for i, tt := range tests {
+		paper := &results.Papers[i]
+		if got := paper.IsCrossListed(); got != tt.crossListed {
+			t.Errorf("%s: expected IsCrossListed %v, got %v", paper.ID, tt.crossListed, got)
+		}
+		if got := paper.IsCrossListedIn(CategoryStatML); got != tt.inStatML {
+			t.Errorf("%s: expected IsCrossListedIn(stat.ML) %v, got %v", paper.ID, tt.inStatML, got)
+		}
+		if got := paper.IsCrossListedIn("stat"); got != tt.inStatArchive {
+			t.Errorf("%s: expected IsCrossListedIn(stat) %v, got %v", paper.ID, tt.inStatArchive, got)
+		}
+	}
+
+	if (&Paper{}).IsCrossListed() || (&Paper{Categories: []string{"cs.AI", "cs.ai"}}).IsCrossListed() ||
+		(&Paper{Categories: []string{"cs.AI"}, primaryCategory: "cs.AI"}).IsCrossListed() {
+		t.Error("Expected papers without other categories not to be cross-listed")
+	}
+}
+
+func TestPaper_IsWithdrawn(t *testing.T) {
+	tests := []struct {
+		name     string
+		paper    Paper
+		expected bool
+	}{
+		{"comment notice", Paper{Comment: "This paper has been withdrawn by the author due to an error in Lemma 2"}, true},
+		{"abstract notice", Paper{Abstract: "This submission has been withdrawn by arXiv administrators."}, true},
+		{"comment prefix", Paper{Comment: "Withdrawn: duplicate of 1234.5678"}, true},
+		{"regular paper", Paper{Comment: "12 pages, 5 figures", Abstract: "We study quantum systems."}, false},
+		{"mentions withdrawal", Paper{Abstract: "We model the withdrawn liquidity in markets."}, false},
+		{"empty", Paper{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.paper.IsWithdrawn(); got != tt.expected {
+				t.Errorf("Expected IsWithdrawn %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestPaper_HasCategory(t *testing.T) {
+	paper := &Paper{Categories: []string{"astro-ph.CO", "hep-th", "cs.AI"}}
+
+	tests := []struct {
+		cat      Category
+		expected bool
+	}{
+		{"astro-ph.CO", true},
+		{"astro-ph", true},
+		{"ASTRO-PH.co", true},
+		{"HEP-TH", true},
+		{CategoryCSAI, true},
+		{"cs", true},
+		{"astro-ph.GA", false},
+		{"astro", false},
+		{"cs.A", false},
+		{"hep-th.X", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		if got := paper.HasCategory(tt.cat); got != tt.expected {
+			t.Errorf("HasCategory(%q): expected %v, got %v", tt.cat, tt.expected, got)
+		}
+	}
+}
+
+func TestPaper_PrimaryCategory(t *testing.T) {
+	feed := `<?xml version="1.0" encoding="UTF-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom" xmlns:arxiv="http://arxiv.org/schemas/atom">
+  <entry>
+    <id>http://arxiv.org/abs/2301.00001v1</id>
+    <published>2023-01-01T00:00:00Z</published>
+    <updated>2023-01-01T00:00:00Z</updated>
+    <arxiv:primary_category term="stat.ML" scheme="http://arxiv.org/schemas/atom"/>
+    <category term="cs.LG" scheme="http://arxiv.org/schemas/atom"/>
+    <category term="stat.ML" scheme="http://arxiv.org/schemas/atom"/>
+  </entry>
+</feed>`
+	results, err := NewClient().parseSearchResponse([]byte(feed), false)
+	if err != nil {
+		t.Fatalf("parseSearchResponse failed: %v", err)
+	}
+	if got := results.Papers[0].PrimaryCategory(); got != "stat.ML" {
+		t.Errorf("Expected the feed's primary category 'stat.ML', got '%s'", got)
+	}
+	if got := results.Papers[0].Clone().PrimaryCategory(); got != "stat.ML" {
+		t.Errorf("Expected clones to keep the primary category, got '%s'", got)
+	}
+
+	// Without the element, the first category is taken
+	paper := &Paper{Categories: []string{"hep-th", "gr-qc"}}
+	if got := paper.PrimaryCategory(); got != "hep-th" {
+		t.Errorf("Expected 'hep-th', got '%s'", got)
+	}
+	if got := (&Paper{}).PrimaryCategory(); got != "" {
+		t.Errorf("Expected empty primary category, got '%s'", got)
+	}
+}
+
+func TestPaper_RelatedLinks(t *testing.T) {
+	feed := `<?xml version="1.0" encoding="UTF-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom">
+  <opensearch:totalResults xmlns:opensearch="http://a9.com/-/spec/opensearch/1.1/">1</opensearch:totalResults>
+  <entry>
+    <id>http://arxiv.org/abs/0704.0001v2</id>
+    <updated>2007-07-24T20:10:27Z</updated>
+    <published>2007-04-02T19:18:42Z</published>
+    <title>Calculation of prompt diphoton production cross sections</title>
+    <summary>A fully differential calculation.</summary>
+    <author><name>C. Balazs</name></author>
+    <arxiv:doi xmlns:arxiv="http://arxiv.org/schemas/atom">10.1103/PhysRevD.76.013009</arxiv:doi>
+    <link title="doi" href="http://dx.doi.org/10.1103/PhysRevD.76.013009" rel="related"/>
+    <link href="http://arxiv.org/abs/0704.0001v2" rel="alternate" type="text/html"/>
+    <link title="pdf" href="http://arxiv.org/pdf/0704.0001v2" rel="related" type="application/pdf"/>
+    <category term="hep-ph" scheme="http://arxiv.org/schemas/atom"/>
+  </entry>
+</feed>`
+
+	results, err := NewClient().parseSearchResponse([]byte(feed), false)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	paper := results.Papers[0]
+
+	var titles []string
+	for _, link := range paper.RelatedLinks() {
+		titles = append(titles, link.Title)
+	}
+	if !slices.Equal(titles, []string{"doi", "pdf"}) {
+		t.Errorf("Expected related links [doi pdf], got %v", titles)
+	}
+
+	doi, ok := paper.DOILink()
+	if !ok || doi.Href != "http://dx.doi.org/10.1103/PhysRevD.76.013009" {
+		t.Errorf("Expected DOI link, got %+v, %v", doi, ok)
+	}
+
+	if _, ok := (&Paper{DOI: "10.1234/test"}).DOILink(); ok {
+		t.Error("Expected no DOI link for a paper without links")
+	}
+}
+
+func TestPaper_WasRevisedAndDaysSincePublished(t *testing.T) {
+	published := time.Date(2023, 1, 2, 19, 0, 0, 0, time.UTC)
+
+	original := &Paper{ID: "2301.00001v1", PublishedAt: published, UpdatedAt: published}
+	if original.WasRevised() {
+		t.Error("Expected a paper with updated == published not to be revised")
+	}
+	revised := &Paper{ID: "2301.00001v2", PublishedAt: published, UpdatedAt: published.AddDate(0, 2, 0)}
+	if !revised.WasRevised() {
+		t.Error("Expected a paper updated two months later to be revised")
+	}
+	if (&Paper{PublishedAt: published, UpdatedAt: published.Add(time.Second)}).WasRevised() {
+		t.Error("Expected a one-second difference to be within the tolerance")
+	}
+
+	recent := &Paper{PublishedAt: time.Now().Add(-(3*24*time.Hour + time.Hour))}
+	if days := recent.DaysSincePublished(); days != 3 {
+		t.Errorf("Expected 3 days since published, got %d", days)
+	}
+	if days := (&Paper{}).DaysSincePublished(); days != -1 {
+		t.Errorf("Expected -1 for an unset PublishedAt, got %d", days)
+	}
+}
+
+func TestPaper_Clone(t *testing.T) {
+	paper := &Paper{
+		ID:         "2301.00001v1",
+		Title:      "Original",
+		Authors:    []Author{{Name: "Alice"}},
+		Categories: []string{"cs.LG"},
+		Links:      []Link{{Href: "http://arxiv.org/abs/2301.00001v1", Rel: "alternate"}},
+	}
+
+	clone := paper.Clone()
+	clone.Title = "Changed"
+	clone.Authors[0].Name = "Mallory"
+	clone.Categories[0] = "math.CO"
+	clone.Links[0].Href = "http://example.com"
+
+	if paper.Title != "Original" || paper.Authors[0].Name != "Alice" || paper.Categories[0] != "cs.LG" ||
+		paper.Links[0].Href != "http://arxiv.org/abs/2301.00001v1" {
+		t.Errorf("Expected the original to be unaffected, got %+v", paper)
+	}
+	if !clone.Equal(paper) {
+		t.Error("Expected the clone to be the same paper")
+	}
+
+	if (*Paper)(nil).Clone() != nil {
+		t.Error("Expected a nil paper to clone to nil")
+	}
+}
@@ -1,46 +1,84 @@
 package arxiv
 
 import (
+	"bytes"
 	"encoding/xml"
+	"errors"
 	"fmt"
+	"io"
+	"regexp"
 	"strings"
 	"time"
+	"unicode/utf8"
+
+	"golang.org/x/net/html/charset"
+)
+
+const (
+	// XML namespaces used by arXiv API feeds
+	atomNamespace       = "http://www.w3.org/2005/Atom"
+	openSearchNamespace = "http://a9.com/-/spec/opensearch/1.1/"
+	arxivNamespace      = "http://arxiv.org/schemas/atom"
+
+	// Prefix of the entry IDs in arXiv feeds
+	absURLPrefix = "http://arxiv.org/abs/"
 )
 
 // XML structures for parsing arXiv API responses
 type atomFeed struct {
 	XMLName xml.Name `xml:"http://www.w3.org/2005/Atom feed"`
-	Title   string   `xml:"title"`
-	ID      string   `xml:"id"`
-	Link    []struct {
+	atomFeedHeader
+	Entries []atomEntry `xml:"entry"`
+}
+
+// atomSummaryFreeFeed decodes a feed without the entry summaries, for Query.SkipAbstract
+type atomSummaryFreeFeed struct {
+	XMLName xml.Name `xml:"http://www.w3.org/2005/Atom feed"`
+	atomFeedHeader
+	Entries []atomEntryMetadata `xml:"entry"`
+}
+
+// atomFeedHeader holds the feed-level elements. The embedding structs declare
+// XMLName themselves since encoding/xml can't set it through an unexported embedded type.
+type atomFeedHeader struct {
+	Title string `xml:"title"`
+	ID    string `xml:"id"`
+	Link  []struct {
 		Href string `xml:"href,attr"`
 		Rel  string `xml:"rel,attr"`
 		Type string `xml:"type,attr"`
 	} `xml:"link"`
-	Updated      string      `xml:"updated"`
-	TotalCount   int         `xml:"http://a9.com/-/spec/opensearch/1.1/ totalResults"`
-	StartIndex   int         `xml:"http://a9.com/-/spec/opensearch/1.1/ startIndex"`
-	ItemsPerPage int         `xml:"http://a9.com/-/spec/opensearch/1.1/ itemsPerPage"`
-	Entries      []atomEntry `xml:"entry"`
+	Updated      string `xml:"updated"`
+	TotalCount   int    `xml:"http://a9.com/-/spec/opensearch/1.1/ totalResults"`
+	StartIndex   int    `xml:"http://a9.com/-/spec/opensearch/1.1/ startIndex"`
+	ItemsPerPage int    `xml:"http://a9.com/-/spec/opensearch/1.1/ itemsPerPage"`
 }
 
 type atomEntry struct {
-	XMLName   xml.Name `xml:"entry"`
-	ID        string   `xml:"id"`
-	Updated   string   `xml:"updated"`
-	Published string   `xml:"published"`
-	Title     string   `xml:"title"`
-	Summary   string   `xml:"summary"`
+	atomEntryMetadata
+	Summary string `xml:"summary"`
+}
+
+type atomEntryMetadata struct {
+	ID        string `xml:"id"`
+	Updated   string `xml:"updated"`
+	Published string `xml:"published"`
+	Title     string `xml:"title"`
 	Authors   []struct {
 		Name string `xml:"name"`
 	} `xml:"author"`
 	DOI        string `xml:"http://arxiv.org/schemas/atom doi"`
 	Comment    string `xml:"http://arxiv.org/schemas/atom comment"`
 	JournalRef string `xml:"http://arxiv.org/schemas/atom journal_ref"`
+	ACMClass   string `xml:"http://arxiv.org/schemas/atom ACM-class"`
+	MSCClass   string `xml:"http://arxiv.org/schemas/atom MSC-class"`
 	Categories []struct {
 		Term   string `xml:"term,attr"`
 		Scheme string `xml:"scheme,attr"`
 	} `xml:"category"`
+	PrimaryCategory struct {
+		Term string `xml:"term,attr"`
+	} `xml:"http://arxiv.org/schemas/atom primary_category"`
 	Links []struct {
 		Href  string `xml:"href,attr"`
 		Rel   string `xml:"rel,attr"`
@@ -49,10 +87,24 @@ type atomEntry struct {
 	} `xml:"link"`
 }
 
-// parseSearchResponse parses the XML response from arXiv API
-func (c *Client) parseSearchResponse(data []byte) (*SearchResults, error) {
+// parseSearchResponse parses the XML response from arXiv API. With skipAbstract the
+// entry summaries are never decoded and every Paper.Abstract is empty.
+// The returned results copy everything they need, so data may be reused afterwards.
+func (c *Client) parseSearchResponse(data []byte, skipAbstract bool) (*SearchResults, error) {
+	data = escapeStrayAmpersands(data)
+
 	var feed atomFeed
-	if err := xml.Unmarshal(data, &feed); err != nil {
+	if skipAbstract {
+		var summaryFree atomSummaryFreeFeed
+		if err := unmarshalXML(data, &summaryFree); err != nil {
+			return nil, fmt.Errorf("failed to parse XML response: %w", err)
+		}
+		feed.atomFeedHeader = summaryFree.atomFeedHeader
+		feed.Entries = make([]atomEntry, len(summaryFree.Entries))
+		for i, metadata := range summaryFree.Entries {
+			feed.Entries[i].atomEntryMetadata = metadata
+		}
+	} else if err := unmarshalXML(data, &feed); err != nil {
 		return nil, fmt.Errorf("failed to parse XML response: %w", err)
 	}
 
@@ -73,6 +125,144 @@ func (c *Client) parseSearchResponse(data []byte) (*SearchResults, error) {
 	}, nil
 }
 
+// utf8BOM is the byte order mark some mirrors and caches prepend to UTF-8 feeds
+var utf8BOM = []byte("\xef\xbb\xbf")
+
+// unmarshalXML decodes data like xml.Unmarshal, after stripping a leading UTF-8 BOM and
+// honoring the encoding declared by the XML header (see charsetReader)
+func unmarshalXML(data []byte, v any) error {
+	decoder := xml.NewDecoder(bytes.NewReader(bytes.TrimPrefix(data, utf8BOM)))
+	// The decoder flattens CharsetReader errors into text, so keep the original to return
+	var charsetErr error
+	decoder.CharsetReader = func(label string, input io.Reader) (io.Reader, error) {
+		reader, err := charsetReader(label, input)
+		charsetErr = err
+		return reader, err
+	}
+	if err := decoder.Decode(v); err != nil {
+		if charsetErr != nil {
+			return charsetErr
+		}
+		return err
+	}
+	return nil
+}
+
+// charsetReader converts input in the named encoding to UTF-8 for the XML decoder, which
+// handles UTF-8 itself, using the WHATWG encodings of golang.org/x/net/html/charset.
+// Unknown labels fail with an error wrapping ErrUnsupportedCharset.
+func charsetReader(label string, input io.Reader) (io.Reader, error) {
+	reader, err := charset.NewReaderLabel(label, input)
+	if err != nil {
+		// The only failure is an unknown label
+		return nil, fmt.Errorf("%w %q", ErrUnsupportedCharset, label)
+	}
+	return reader, nil
+}
+
+// xmlReferencePattern matches the entity and character references valid in XML
+var xmlReferencePattern = regexp.MustCompile(`^&(?:amp|lt|gt|quot|apos|#[0-9]+|#x[0-9a-fA-F]+);`)
+
+// Longest reference worth checking for; longer matches would be absurd character references
+const maxXMLReferenceLength = 16
+
+// xmlLiteralSections are the sections whose content the XML decoder takes literally
+var xmlLiteralSections = [...]struct{ start, end []byte }{
+	{[]byte("<![CDATA["), []byte("]]>")},
+	{[]byte("<!--"), []byte("-->")},
+}
+
+// escapeStrayAmpersands escapes every '&' in data that doesn't start an XML reference as
+// "&amp;". arXiv feeds occasionally contain raw ampersands, e.g. "Q&A" in a title, which
+// the XML decoder rejects, failing the whole page. Valid references are kept, as is the
+// content of CDATA sections and comments, and unknown HTML entities such as "&nbsp;"
+// become literal text. data itself is returned when nothing needs escaping.
+func escapeStrayAmpersands(data []byte) []byte {
+	if bytes.IndexByte(data, '&') < 0 {
+		return data
+	}
+
+	var out []byte
+	last := 0
+	for i := 0; i < len(data); i++ {
+		switch data[i] {
+		case '<':
+			for _, section := range xmlLiteralSections {
+				if !bytes.HasPrefix(data[i:], section.start) {
+					continue
+				}
+				end := bytes.Index(data[i+len(section.start):], section.end)
+				if end < 0 {
+					i = len(data)
+				} else {
+					i += len(section.start) + end + len(section.end) - 1
+				}
+				break
+			}
+		case '&':
+			if xmlReferencePattern.Match(data[i:min(i+maxXMLReferenceLength, len(data))]) {
+				continue
+			}
+			out = append(out, data[last:i]...)
+			out = append(out, "&amp;"...)
+			last = i + 1
+		}
+	}
+	if out == nil {
+		return data
+	}
+	return append(out, data[last:]...)
+}
+
+// newParseError wraps a parse failure in an APIError. Truncated bodies, usually caused by
+// the connection closing mid-stream, are marked retryable and wrap ErrTruncatedResponse;
+// malformed XML and documents that aren't an Atom feed are not.
+func newParseError(message string, err error) *APIError {
+	if isTruncatedXML(err) {
+		apiErr := NewAPIError(ErrorTypeParsing, message, fmt.Errorf("%w: %w", ErrTruncatedResponse, err))
+		apiErr.Retry = true
+		return apiErr
+	}
+	return NewAPIError(ErrorTypeParsing, message, err)
+}
+
+// isTruncatedXML reports whether err indicates the XML input ended unexpectedly
+func isTruncatedXML(err error) bool {
+	if errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+	var syntaxErr *xml.SyntaxError
+	return errors.As(err, &syntaxErr) && syntaxErr.Msg == "unexpected EOF"
+}
+
+// Maximum length of an error explanation taken from a plain-text response body
+const maxErrorExplanationLength = 300
+
+// parseErrorExplanation extracts arXiv's explanation from the body of an error response:
+// the summary of the error entry when it is an Atom feed, or the text itself when it is
+// plain text. It returns "" when the body holds neither, e.g. an HTML error page.
+func parseErrorExplanation(data []byte) string {
+	var feed struct {
+		Entries []struct {
+			Summary string `xml:"summary"`
+		} `xml:"entry"`
+	}
+	if err := unmarshalXML(data, &feed); err == nil {
+		for _, entry := range feed.Entries {
+			if summary := collapseWhitespace(entry.Summary); summary != "" {
+				return summary
+			}
+		}
+		return ""
+	}
+
+	text := collapseWhitespace(string(data))
+	if strings.HasPrefix(text, "<") || !utf8.ValidString(text) {
+		return ""
+	}
+	return truncateText(text, maxErrorExplanationLength)
+}
+
 // convertEntryToPaper converts an XML entry to a Paper struct
 func (c *Client) convertEntryToPaper(entry atomEntry) (*Paper, error) {
 	// Parse dates
@@ -97,10 +287,13 @@ func (c *Client) convertEntryToPaper(entry atomEntry) (*Paper, error) {
 		}
 	}
 
-	// Convert categories
-	categories := make([]string, len(entry.Categories))
-	for i, cat := range entry.Categories {
-		categories[i] = cat.Term
+	// Convert categories, skipping terms from other classification schemes
+	categories := make([]string, 0, len(entry.Categories))
+	for _, cat := range entry.Categories {
+		if cat.Scheme != "" && cat.Scheme != arxivNamespace {
+			continue
+		}
+		categories = append(categories, cat.Term)
 	}
 
 	// Convert links
@@ -114,10 +307,17 @@ func (c *Client) convertEntryToPaper(entry atomEntry) (*Paper, error) {
 		}
 	}
 
+	title := strings.TrimSpace(entry.Title)
+	abstract := strings.TrimSpace(entry.Summary)
+	if !c.options.PreserveWhitespace {
+		title = collapseWhitespace(title)
+		abstract = collapseWhitespace(abstract)
+	}
+
 	return &Paper{
 		ID:          id,
-		Title:       strings.TrimSpace(entry.Title),
-		Abstract:    strings.TrimSpace(entry.Summary),
+		Title:       title,
+		Abstract:    abstract,
 		Authors:     authors,
 		Categories:  categories,
 		PublishedAt: publishedAt,
@@ -125,16 +325,58 @@ func (c *Client) convertEntryToPaper(entry atomEntry) (*Paper, error) {
 		DOI:         entry.DOI,
 		JournalRef:  entry.JournalRef,
 		Comment:     entry.Comment,
+		ACMClass:    strings.TrimSpace(entry.ACMClass),
+		MSCClass:    strings.TrimSpace(entry.MSCClass),
 		Links:       links,
+
+		primaryCategory: strings.TrimSpace(entry.PrimaryCategory.Term),
 	}, nil
 }
 
-// extractArxivID extracts the arXiv ID from the full ID URL
-// Example: "http://arxiv.org/abs/1234.5678v1" -> "1234.5678v1"
+// collapseWhitespace replaces every run of whitespace in s with a single space
+func collapseWhitespace(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// extractArxivID extracts the canonical arXiv ID from an ID or any arxiv.org abs/pdf URL.
+// Examples:
+//
+//	"http://arxiv.org/abs/1234.5678v1"              -> "1234.5678v1"
+//	"https://www.arxiv.org/pdf/1234.5678v1.pdf?x=1" -> "1234.5678v1"
+//	"arXiv:quant-ph/0301001"                        -> "quant-ph/0301001"
+//
+// Anything that isn't an arxiv.org URL is returned trimmed but otherwise unchanged.
 func extractArxivID(fullID string) string {
-	// Remove the URL prefix to get just the ID
-	if strings.HasPrefix(fullID, "http://arxiv.org/abs/") {
-		return strings.TrimPrefix(fullID, "http://arxiv.org/abs/")
+	id := strings.TrimSpace(fullID)
+
+	rest := id
+	if i := strings.Index(rest, "://"); i >= 0 {
+		rest = rest[i+len("://"):]
 	}
-	return fullID
+	host, path, found := strings.Cut(rest, "/")
+	if found && isArxivHost(host) {
+		if i := strings.IndexAny(path, "?#"); i >= 0 {
+			path = path[:i]
+		}
+		path = strings.Trim(path, "/")
+		for _, prefix := range []string{"abs/", "pdf/"} {
+			if strings.HasPrefix(path, prefix) {
+				path = strings.TrimPrefix(path, prefix)
+				path = strings.TrimSuffix(path, ".pdf")
+				break
+			}
+		}
+		id = path
+	}
+
+	if len(id) > len("arXiv:") && strings.EqualFold(id[:len("arXiv:")], "arXiv:") {
+		id = id[len("arXiv:"):]
+	}
+	return id
+}
+
+// isArxivHost reports whether host is arxiv.org or one of its subdomains (www, export)
+func isArxivHost(host string) bool {
+	host = strings.ToLower(host)
+	return host == "arxiv.org" || strings.HasSuffix(host, ".arxiv.org")
 }
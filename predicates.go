@@ -0,0 +1,28 @@
+package arxiv
+
+import "strings"
+
+// Predicate builders for use with FilterSeq, e.g. FilterSeq(it.All(), HasCategory(CategoryCSLG))
+
+// MinAuthors returns a predicate matching papers with at least n authors
+func MinAuthors(n int) func(*Paper) bool {
+	return func(p *Paper) bool {
+		return len(p.Authors) >= n
+	}
+}
+
+// HasCategory returns a predicate matching papers listed in cat; see Paper.HasCategory
+func HasCategory(cat Category) func(*Paper) bool {
+	return func(p *Paper) bool {
+		return p.HasCategory(cat)
+	}
+}
+
+// TitleContains returns a predicate matching papers whose title contains substr.
+// The match is case-insensitive.
+func TitleContains(substr string) func(*Paper) bool {
+	lower := strings.ToLower(substr)
+	return func(p *Paper) bool {
+		return strings.Contains(strings.ToLower(p.Title), lower)
+	}
+}
@@ -0,0 +1,53 @@
+package arxiv
+
+import (
+	"slices"
+	"testing"
+)
+
+func testPapers() []*Paper {
+	return []*Paper{
+		{ID: "1", Title: "Deep Learning for Robots", Authors: []Author{{Name: "A"}}, Categories: []string{"cs.RO", "cs.LG"}},
+		{ID: "2", Title: "Quantum Error Correction", Authors: []Author{{Name: "A"}, {Name: "B"}, {Name: "C"}}, Categories: []string{"quant-ph"}},
+		{ID: "3", Title: "Learning to Learn", Authors: []Author{{Name: "A"}, {Name: "B"}}, Categories: []string{"cs.LG", "stat.ML"}},
+	}
+}
+
+// filteredIDs returns the IDs of the test papers matching pred
+func filteredIDs(pred func(*Paper) bool) []string {
+	var ids []string
+	for paper := range FilterSeq(slices.Values(testPapers()), pred) {
+		ids = append(ids, paper.ID)
+	}
+	return ids
+}
+
+func TestMinAuthors(t *testing.T) {
+	if got := filteredIDs(MinAuthors(2)); !slices.Equal(got, []string{"2", "3"}) {
+		t.Errorf("Expected [2 3], got %v", got)
+	}
+	if got := filteredIDs(MinAuthors(0)); len(got) != 3 {
+		t.Errorf("Expected all papers for MinAuthors(0), got %v", got)
+	}
+}
+
+func TestHasCategory(t *testing.T) {
+	if got := filteredIDs(HasCategory(CategoryCSLG)); !slices.Equal(got, []string{"1", "3"}) {
+		t.Errorf("Expected [1 3], got %v", got)
+	}
+	if got := filteredIDs(HasCategory(CategoryMathCO)); len(got) != 0 {
+		t.Errorf("Expected no papers in math.CO, got %v", got)
+	}
+	if got := filteredIDs(HasCategory("cs")); !slices.Equal(got, []string{"1", "3"}) {
+		t.Errorf("Expected archive-level match [1 3], got %v", got)
+	}
+}
+
+func TestTitleContains(t *testing.T) {
+	if got := filteredIDs(TitleContains("learn")); !slices.Equal(got, []string{"1", "3"}) {
+		t.Errorf("Expected case-insensitive match [1 3], got %v", got)
+	}
+	if got := filteredIDs(TitleContains("QUANTUM")); !slices.Equal(got, []string{"2"}) {
+		t.Errorf("Expected [2], got %v", got)
+	}
+}
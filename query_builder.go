@@ -3,18 +3,25 @@ package arxiv
 import (
 	"context"
 	"fmt"
+	"iter"
+	"slices"
 	"strings"
 	"time"
 )
 
 // QueryBuilder provides a fluent interface for building arXiv queries
 type QueryBuilder struct {
-	client      *Client
+	client      Searcher
 	searchTerms []string
 	categories  []Category
+	allCats     []Category
+	primaryCat  Category
 	authors     []string
 	titles      []string
 	abstracts   []string
+	allFields   []string
+	anyFields   []string
+	exclAuthors []string
 	dateFrom    *time.Time
 	dateTo      *time.Time
 	sortBy      SortCriterion
@@ -24,6 +31,30 @@ type QueryBuilder struct {
 	start       int
 	idList      []string
 	errors      []error
+	explicit    builderSetting // Scalar settings set by the caller, which Merge carries over
+}
+
+// builderSetting flags a scalar QueryBuilder setting
+type builderSetting uint8
+
+const (
+	settingSortBy builderSetting = 1 << iota
+	settingSortOrder
+	settingMaxResults
+	settingLimit
+	settingStart
+)
+
+// NewQueryBuilder creates a QueryBuilder that runs its queries through searcher.
+// Most callers should use Client.NewQuery instead.
+func NewQueryBuilder(searcher Searcher) *QueryBuilder {
+	return &QueryBuilder{
+		client:     searcher,
+		maxResults: defaultMaxResults,
+		limit:      defaultLimit,
+		sortBy:     SortByRelevance,
+		sortOrder:  SortOrderDescending,
+	}
 }
 
 // SearchQuery adds a general search term
@@ -52,6 +83,44 @@ func (qb *QueryBuilder) Categories(cats ...Category) *QueryBuilder {
 	return qb
 }
 
+// AllCategories adds category filters that must all match, e.g. papers cross-listed
+// in both cs.LG and stat.ML: (cat:cs.LG AND cat:stat.ML).
+// Unlike Categories, which matches papers in any of the given categories (OR),
+// this matches only papers listed in every one of them (AND).
+func (qb *QueryBuilder) AllCategories(cats ...Category) *QueryBuilder {
+	for _, cat := range cats {
+		if cat != "" {
+			qb.allCats = append(qb.allCats, cat)
+		}
+	}
+	return qb
+}
+
+// CrossListedIn adds a filter for papers listed in cat. arXiv's search can't tell primary
+// categories from cross-lists, so the results also include papers whose primary category is
+// cat; filter them with Paper.IsCrossListedIn after fetching. Like AllCategories, it must
+// match in addition to any other category filters.
+func (qb *QueryBuilder) CrossListedIn(cat Category) *QueryBuilder {
+	return qb.AllCategories(cat)
+}
+
+// PrimaryCategory restricts the results to papers whose primary category is cat. arXiv's
+// search matches cross-lists too, so this adds a cat: clause like AllCategories and has
+// Search drop the cross-listed papers after fetching. Pages may then hold fewer papers
+// than requested, and iterators fetch extra pages to reach the Limit; when most matches
+// are cross-lists, that can take many more requests. A later call replaces cat.
+func (qb *QueryBuilder) PrimaryCategory(cat Category) *QueryBuilder {
+	if cat == "" {
+		return qb
+	}
+	if i := slices.Index(qb.allCats, qb.primaryCat); qb.primaryCat != "" && i >= 0 {
+		qb.allCats = slices.Delete(qb.allCats, i, i+1)
+	}
+	qb.primaryCat = cat
+	qb.allCats = append(qb.allCats, cat)
+	return qb
+}
+
 // Author adds an author filter
 func (qb *QueryBuilder) Author(author string) *QueryBuilder {
 	if author != "" {
@@ -70,6 +139,24 @@ func (qb *QueryBuilder) Authors(authors ...string) *QueryBuilder {
 	return qb
 }
 
+// ExcludeAuthor drops papers by author, e.g. Category(CategoryCSLG).ExcludeAuthor("Geoffrey
+// Hinton") adds ANDNOT au:"Geoffrey Hinton". Names of several words are quoted so they
+// match as a phrase. arXiv needs something to exclude from, so a query made only of
+// exclusions is invalid.
+func (qb *QueryBuilder) ExcludeAuthor(name string) *QueryBuilder {
+	name = strings.Join(strings.Fields(strings.ReplaceAll(name, `"`, "")), " ")
+	if name == "" {
+		return qb
+	}
+	if strings.Contains(name, " ") {
+		name = `"` + name + `"`
+	}
+	if !slices.Contains(qb.exclAuthors, name) {
+		qb.exclAuthors = append(qb.exclAuthors, name)
+	}
+	return qb
+}
+
 // Title adds a title filter
 func (qb *QueryBuilder) Title(title string) *QueryBuilder {
 	if title != "" {
@@ -86,6 +173,47 @@ func (qb *QueryBuilder) Abstract(abstract string) *QueryBuilder {
 	return qb
 }
 
+// All adds a filter matching text in any field (title, abstract, authors, comments).
+// Unlike Iterator.All, which ranges over fetched papers, this builds an all: clause.
+func (qb *QueryBuilder) All(text string) *QueryBuilder {
+	if text != "" {
+		qb.allFields = append(qb.allFields, text)
+	}
+	return qb
+}
+
+// AnyField adds a filter matching text in any of fields, e.g. AnyField("transformer",
+// FieldTitle, FieldAbstract) adds (ti:transformer OR abs:transformer), ANDed with the
+// other filters like every field filter. Without fields it matches text in all fields.
+// An undefined field makes the query invalid.
+func (qb *QueryBuilder) AnyField(text string, fields ...QueryField) *QueryBuilder {
+	if text == "" {
+		return qb
+	}
+	if len(fields) == 0 {
+		fields = []QueryField{FieldAll}
+	}
+
+	terms := make([]string, 0, len(fields))
+	for _, field := range fields {
+		if !field.IsValid() {
+			qb.errors = append(qb.errors, NewAPIError(ErrorTypeInvalidQuery, fmt.Sprintf("invalid query field %q", field), nil))
+			return qb
+		}
+		term := fmt.Sprintf("%s:%s", field, text)
+		if !slices.Contains(terms, term) {
+			terms = append(terms, term)
+		}
+	}
+
+	clause := terms[0]
+	if len(terms) > 1 {
+		clause = fmt.Sprintf("(%s)", strings.Join(terms, " OR "))
+	}
+	qb.anyFields = append(qb.anyFields, clause)
+	return qb
+}
+
 // DateRange sets the date range filter
 func (qb *QueryBuilder) DateRange(from, to time.Time) *QueryBuilder {
 	qb.dateFrom = &from
@@ -109,6 +237,28 @@ func (qb *QueryBuilder) DateTo(to time.Time) *QueryBuilder {
 func (qb *QueryBuilder) SortBy(criterion SortCriterion, order SortOrder) *QueryBuilder {
 	qb.sortBy = criterion
 	qb.sortOrder = order
+	qb.explicit |= settingSortBy | settingSortOrder
+	return qb
+}
+
+// SortByField sets the sort criterion, keeping the current order (descending by default)
+func (qb *QueryBuilder) SortByField(criterion SortCriterion) *QueryBuilder {
+	qb.sortBy = criterion
+	qb.explicit |= settingSortBy
+	return qb
+}
+
+// Ascending sets the sort order to ascending, keeping the current criterion
+func (qb *QueryBuilder) Ascending() *QueryBuilder {
+	qb.sortOrder = SortOrderAscending
+	qb.explicit |= settingSortOrder
+	return qb
+}
+
+// Descending sets the sort order to descending, keeping the current criterion
+func (qb *QueryBuilder) Descending() *QueryBuilder {
+	qb.sortOrder = SortOrderDescending
+	qb.explicit |= settingSortOrder
 	return qb
 }
 
@@ -116,6 +266,7 @@ func (qb *QueryBuilder) SortBy(criterion SortCriterion, order SortOrder) *QueryB
 func (qb *QueryBuilder) MaxResults(max int) *QueryBuilder {
 	if max > 0 {
 		qb.maxResults = max
+		qb.explicit |= settingMaxResults
 	} else {
 		qb.errors = append(qb.errors, fmt.Errorf("max results must be positive, got %d", max))
 	}
@@ -126,6 +277,7 @@ func (qb *QueryBuilder) MaxResults(max int) *QueryBuilder {
 func (qb *QueryBuilder) Limit(limit int) *QueryBuilder {
 	if limit >= 0 {
 		qb.limit = limit
+		qb.explicit |= settingLimit
 	} else {
 		qb.errors = append(qb.errors, fmt.Errorf("limit must be non-negative, got %d", limit))
 	}
@@ -136,6 +288,7 @@ func (qb *QueryBuilder) Limit(limit int) *QueryBuilder {
 func (qb *QueryBuilder) Start(start int) *QueryBuilder {
 	if start >= 0 {
 		qb.start = start
+		qb.explicit |= settingStart
 	} else {
 		qb.errors = append(qb.errors, fmt.Errorf("start index must be non-negative, got %d", start))
 	}
@@ -155,7 +308,8 @@ func (qb *QueryBuilder) AND() *QueryBuilder {
 	return qb
 }
 
-// OR adds an OR operation to the search query
+// OR adds an OR operation to the search query.
+// AND and ANDNOT bind tighter, so a AND b OR c is sent as ((a AND b) OR c).
 func (qb *QueryBuilder) OR() *QueryBuilder {
 	// This is a marker for complex query building
 	qb.searchTerms = append(qb.searchTerms, "OR")
@@ -169,72 +323,251 @@ func (qb *QueryBuilder) ANDNOT() *QueryBuilder {
 	return qb
 }
 
-// buildSearchQuery constructs the final search query string
-func (qb *QueryBuilder) buildSearchQuery() string {
-	var queryParts []string
+// Merge appends other's filters (search terms, categories, authors, titles, abstracts,
+// all-fields text and IDs) and accumulated errors to qb. Search terms from both builders
+// are grouped per builder and joined with AND. Scalar settings follow last-writer-wins:
+// any sort, date range, MaxResults, Limit or Start explicitly set on other overrides qb's,
+// while other's defaults, including the client's DefaultMaxResults, leave qb's in place.
+func (qb *QueryBuilder) Merge(other *QueryBuilder) *QueryBuilder {
+	if other == nil {
+		return qb
+	}
 
-	// Add search terms
-	if len(qb.searchTerms) > 0 {
-		// Handle complex queries with operators
-		searchQuery := strings.Join(qb.searchTerms, " ")
-		if searchQuery != "" {
-			queryParts = append(queryParts, fmt.Sprintf("(%s)", searchQuery))
+	if len(qb.searchTerms) > 0 && len(other.searchTerms) > 0 {
+		// Group each side so its operators can't bind across the AND
+		qb.searchTerms = append(groupSearchTerms(qb.searchTerms), "AND")
+		qb.searchTerms = append(qb.searchTerms, groupSearchTerms(other.searchTerms)...)
+	} else {
+		qb.searchTerms = append(qb.searchTerms, other.searchTerms...)
+	}
+	qb.categories = append(qb.categories, other.categories...)
+	if other.primaryCat != "" {
+		// other's primary category replaces qb's, as a later PrimaryCategory call would
+		if i := slices.Index(qb.allCats, qb.primaryCat); qb.primaryCat != "" && i >= 0 {
+			qb.allCats = slices.Delete(qb.allCats, i, i+1)
+		}
+		qb.primaryCat = other.primaryCat
+	}
+	qb.allCats = append(qb.allCats, other.allCats...)
+	qb.authors = append(qb.authors, other.authors...)
+	qb.titles = append(qb.titles, other.titles...)
+	qb.abstracts = append(qb.abstracts, other.abstracts...)
+	qb.allFields = append(qb.allFields, other.allFields...)
+	qb.anyFields = append(qb.anyFields, other.anyFields...)
+	qb.exclAuthors = append(qb.exclAuthors, other.exclAuthors...)
+	qb.idList = append(qb.idList, other.idList...)
+	qb.errors = append(qb.errors, other.errors...)
+
+	if other.dateFrom != nil {
+		qb.dateFrom = other.dateFrom
+	}
+	if other.dateTo != nil {
+		qb.dateTo = other.dateTo
+	}
+	if other.explicit&settingSortBy != 0 {
+		qb.sortBy = other.sortBy
+	}
+	if other.explicit&settingSortOrder != 0 {
+		qb.sortOrder = other.sortOrder
+	}
+	if other.explicit&settingMaxResults != 0 {
+		qb.maxResults = other.maxResults
+	}
+	if other.explicit&settingLimit != 0 {
+		qb.limit = other.limit
+	}
+	if other.explicit&settingStart != 0 {
+		qb.start = other.start
+	}
+	qb.explicit |= other.explicit
+	return qb
+}
+
+// groupSearchTerms collapses terms into a single term holding their expression.
+// searchExpression parenthesizes it when combined with other operands.
+func groupSearchTerms(terms []string) []string {
+	if len(terms) <= 1 {
+		return terms
+	}
+	return []string{searchExpression(terms)}
+}
+
+// CategoryFilter returns a search clause matching papers in any of cats,
+// e.g. "cat:cs.AI" or "(cat:cs.AI OR cat:cs.LG)", for use in Query.SearchQuery
+func CategoryFilter(cats ...Category) string {
+	values := make([]string, 0, len(cats))
+	for _, cat := range cats {
+		if cat != "" {
+			values = append(values, string(cat))
 		}
 	}
+	return fieldClause("cat", values, "OR")
+}
 
-	// Add category filters
-	if len(qb.categories) > 0 {
-		var catQueries []string
-		for _, cat := range qb.categories {
-			catQueries = append(catQueries, fmt.Sprintf("cat:%s", string(cat)))
+// AuthorFilter returns a search clause matching papers by any of names,
+// e.g. "au:Hinton" or "(au:Hinton OR au:LeCun)", for use in Query.SearchQuery
+func AuthorFilter(names ...string) string {
+	values := make([]string, 0, len(names))
+	for _, name := range names {
+		if name != "" {
+			values = append(values, name)
 		}
-		if len(catQueries) == 1 {
-			queryParts = append(queryParts, catQueries[0])
-		} else {
-			queryParts = append(queryParts, fmt.Sprintf("(%s)", strings.Join(catQueries, " OR ")))
+	}
+	return fieldClause("au", values, "OR")
+}
+
+// fieldClause joins field:value terms with op, parenthesizing when there is more than one.
+// It returns "" when values is empty.
+func fieldClause(field string, values []string, op string) string {
+	terms := make([]string, len(values))
+	for i, value := range values {
+		terms[i] = fmt.Sprintf("%s:%s", field, value)
+	}
+
+	switch len(terms) {
+	case 0:
+		return ""
+	case 1:
+		return terms[0]
+	default:
+		return fmt.Sprintf("(%s)", strings.Join(terms, " "+op+" "))
+	}
+}
+
+// categoryStrings converts categories to their string values
+func categoryStrings(cats []Category) []string {
+	values := make([]string, len(cats))
+	for i, cat := range cats {
+		values[i] = string(cat)
+	}
+	return values
+}
+
+// isSearchOperator reports whether term is one of the AND, OR and ANDNOT markers
+func isSearchOperator(term string) bool {
+	return term == "AND" || term == "OR" || term == "ANDNOT"
+}
+
+// validateSearchTerms rejects operator markers without a search term on both sides,
+// e.g. a leading AND(), a trailing OR() or AND().OR()
+func validateSearchTerms(terms []string) error {
+	prev := ""
+	for i, term := range terms {
+		if isSearchOperator(term) {
+			switch {
+			case i == 0:
+				return NewAPIError(ErrorTypeInvalidQuery, fmt.Sprintf("search query starts with operator %s", term), nil)
+			case isSearchOperator(prev):
+				return NewAPIError(ErrorTypeInvalidQuery, fmt.Sprintf("consecutive operators %s %s in search query", prev, term), nil)
+			case i == len(terms)-1:
+				return NewAPIError(ErrorTypeInvalidQuery, fmt.Sprintf("search query ends with operator %s", term), nil)
+			}
 		}
+		prev = term
 	}
+	return nil
+}
 
-	// Add author filters
-	if len(qb.authors) > 0 {
-		var authQueries []string
-		for _, author := range qb.authors {
-			authQueries = append(authQueries, fmt.Sprintf("au:%s", author))
+// searchExpression joins search terms and operator markers into an unambiguous expression.
+// Adjacent terms without an operator form a single operand. AND and ANDNOT bind tighter
+// than OR, so "a AND b OR c d" becomes "(a AND b) OR (c d)". Operators missing an
+// operand on either side, which validateSearchTerms rejects, are dropped.
+func searchExpression(terms []string) string {
+	// Collapse the terms into alternating operands and operators
+	var tokens []string
+	var operand []string
+	pendingOp := ""
+	for _, term := range terms {
+		if !isSearchOperator(term) {
+			operand = append(operand, term)
+			continue
 		}
-		if len(authQueries) == 1 {
-			queryParts = append(queryParts, authQueries[0])
-		} else {
-			queryParts = append(queryParts, fmt.Sprintf("(%s)", strings.Join(authQueries, " OR ")))
+		if len(operand) > 0 {
+			if pendingOp != "" {
+				tokens = append(tokens, pendingOp)
+			}
+			tokens = append(tokens, strings.Join(operand, " "))
+			operand = nil
+			pendingOp = term
+		} else if len(tokens) > 0 {
+			pendingOp = term
+		}
+	}
+	if len(operand) > 0 {
+		if pendingOp != "" {
+			tokens = append(tokens, pendingOp)
 		}
+		tokens = append(tokens, strings.Join(operand, " "))
 	}
 
-	// Add title filters
-	if len(qb.titles) > 0 {
-		var titleQueries []string
-		for _, title := range qb.titles {
-			titleQueries = append(titleQueries, fmt.Sprintf("ti:%s", title))
+	if len(tokens) <= 1 {
+		return strings.Join(tokens, "")
+	}
+
+	// Split on OR, keeping the AND/ANDNOT chains together
+	var groups [][]string
+	group := []string{}
+	for _, token := range tokens {
+		if token == "OR" {
+			groups = append(groups, group)
+			group = []string{}
+			continue
 		}
-		if len(titleQueries) == 1 {
-			queryParts = append(queryParts, titleQueries[0])
-		} else {
-			queryParts = append(queryParts, fmt.Sprintf("(%s)", strings.Join(titleQueries, " OR ")))
+		if !isSearchOperator(token) && strings.Contains(token, " ") {
+			token = fmt.Sprintf("(%s)", token)
 		}
+		group = append(group, token)
 	}
+	groups = append(groups, group)
 
-	// Add abstract filters
-	if len(qb.abstracts) > 0 {
-		var absQueries []string
-		for _, abstract := range qb.abstracts {
-			absQueries = append(absQueries, fmt.Sprintf("abs:%s", abstract))
+	parts := make([]string, len(groups))
+	for i, group := range groups {
+		parts[i] = strings.Join(group, " ")
+		if len(group) > 1 && len(groups) > 1 {
+			parts[i] = fmt.Sprintf("(%s)", parts[i])
 		}
-		if len(absQueries) == 1 {
-			queryParts = append(queryParts, absQueries[0])
-		} else {
-			queryParts = append(queryParts, fmt.Sprintf("(%s)", strings.Join(absQueries, " OR ")))
+	}
+	return strings.Join(parts, " OR ")
+}
+
+// buildSearchQuery constructs the final search query string
+func (qb *QueryBuilder) buildSearchQuery() string {
+	queryParts := qb.includeClauses()
+	searchQuery := strings.Join(queryParts, " AND ")
+	if len(queryParts) == 0 || len(qb.exclAuthors) == 0 {
+		return searchQuery
+	}
+	if len(queryParts) > 1 {
+		searchQuery = fmt.Sprintf("(%s)", searchQuery)
+	}
+	return searchQuery + " ANDNOT " + fieldClause("au", qb.exclAuthors, "OR")
+}
+
+// includeClauses returns the search terms and field filters to be ANDed, without the exclusions
+func (qb *QueryBuilder) includeClauses() []string {
+	var queryParts []string
+
+	// Add search terms
+	if searchQuery := searchExpression(qb.searchTerms); searchQuery != "" {
+		queryParts = append(queryParts, fmt.Sprintf("(%s)", searchQuery))
+	}
+
+	// Add field filters: categories, required categories, authors, titles, abstracts, all fields,
+	// then the multi-field groups
+	for _, clause := range append([]string{
+		fieldClause("cat", categoryStrings(qb.categories), "OR"),
+		fieldClause("cat", categoryStrings(qb.allCats), "AND"),
+		fieldClause("au", qb.authors, "OR"),
+		fieldClause("ti", qb.titles, "OR"),
+		fieldClause("abs", qb.abstracts, "OR"),
+		fieldClause("all", qb.allFields, "OR"),
+	}, qb.anyFields...) {
+		if clause != "" {
+			queryParts = append(queryParts, clause)
 		}
 	}
 
-	return strings.Join(queryParts, " AND ")
+	return queryParts
 }
 
 // buildQuery constructs the Query object
@@ -252,12 +585,19 @@ func (qb *QueryBuilder) buildQuery() (*Query, error) {
 		SortOrder:         string(qb.sortOrder),
 		SubmittedDateFrom: qb.dateFrom,
 		SubmittedDateTo:   qb.dateTo,
+		PrimaryCategory:   string(qb.primaryCat),
 	}
 
 	// Set ID list or search query
 	if len(qb.idList) > 0 {
 		query.IDList = qb.idList
 	} else {
+		if err := validateSearchTerms(qb.searchTerms); err != nil {
+			return nil, err
+		}
+		if len(qb.exclAuthors) > 0 && len(qb.includeClauses()) == 0 {
+			return nil, NewAPIError(ErrorTypeInvalidQuery, "search query has only exclusions: ANDNOT needs a filter to exclude from", nil)
+		}
 		searchQuery := qb.buildSearchQuery()
 		if searchQuery == "" && len(qb.idList) == 0 {
 			return nil, NewAPIError(ErrorTypeInvalidQuery, "either search query or ID list must be provided", nil)
@@ -291,6 +631,13 @@ func (qb *QueryBuilder) Iterator(ctx context.Context) *Iterator {
 	return NewIterator(qb.client, query, ctx)
 }
 
+// Stream returns a sequence over every paper matching the query, fetching pages as needed.
+// It is shorthand for qb.Iterator(ctx).AllWithError(): an invalid query or a failed request
+// is yielded as the error of the final element.
+func (qb *QueryBuilder) Stream(ctx context.Context) iter.Seq2[*Paper, error] {
+	return qb.Iterator(ctx).AllWithError()
+}
+
 // Validate checks if the query builder configuration is valid
 func (qb *QueryBuilder) Validate() error {
 	if len(qb.errors) > 0 {
@@ -301,6 +648,12 @@ func (qb *QueryBuilder) Validate() error {
 		return NewAPIError(ErrorTypeInvalidQuery, "either search query or ID list must be provided", nil)
 	}
 
+	if len(qb.idList) == 0 {
+		if err := validateSearchTerms(qb.searchTerms); err != nil {
+			return err
+		}
+	}
+
 	if qb.maxResults <= 0 {
 		return NewAPIError(ErrorTypeInvalidQuery, "max results must be positive", nil)
 	}
@@ -309,5 +662,9 @@ func (qb *QueryBuilder) Validate() error {
 		return NewAPIError(ErrorTypeInvalidQuery, "start index must be non-negative", nil)
 	}
 
+	if !qb.sortBy.IsValid() || !qb.sortOrder.IsValid() {
+		return NewAPIError(ErrorTypeInvalidQuery, fmt.Sprintf("invalid sort %q/%q", qb.sortBy, qb.sortOrder), nil)
+	}
+
 	return nil
 }
@@ -1,6 +1,13 @@
 package arxiv
 
 import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 )
@@ -48,6 +55,60 @@ func TestQueryBuilder_Categories(t *testing.T) {
 	}
 }
 
+func TestQueryBuilder_AllCategories(t *testing.T) {
+	client := NewClient()
+	qb := client.NewQuery().AllCategories(CategoryCSLG, CategoryStatML)
+
+	query, err := qb.buildQuery()
+	if err != nil {
+		t.Fatalf("buildQuery failed: %v", err)
+	}
+
+	expected := "(cat:cs.LG AND cat:stat.ML)"
+	if query.SearchQuery != expected {
+		t.Errorf("Expected search query '%s', got '%s'", expected, query.SearchQuery)
+	}
+
+	// Combined with OR-joined categories
+	qb = client.NewQuery().Categories(CategoryCSAI, CategoryCSCL).AllCategories(CategoryCSLG, CategoryStatML)
+	query, err = qb.buildQuery()
+	if err != nil {
+		t.Fatalf("buildQuery failed: %v", err)
+	}
+
+	expected = "(cat:cs.AI OR cat:cs.CL) AND (cat:cs.LG AND cat:stat.ML)"
+	if query.SearchQuery != expected {
+		t.Errorf("Expected search query '%s', got '%s'", expected, query.SearchQuery)
+	}
+}
+
+func TestCategoryAndAuthorFilter(t *testing.T) {
+	client := NewClient()
+	tests := []struct {
+		filter  string
+		builder *QueryBuilder
+	}{
+		{CategoryFilter(CategoryCSAI), client.NewQuery().Category(CategoryCSAI)},
+		{CategoryFilter(CategoryCSAI, CategoryCSLG), client.NewQuery().Categories(CategoryCSAI, CategoryCSLG)},
+		{AuthorFilter("Einstein"), client.NewQuery().Author("Einstein")},
+		{AuthorFilter("Einstein", "", "Bohr"), client.NewQuery().Authors("Einstein", "Bohr")},
+	}
+
+	for _, tt := range tests {
+		query, err := tt.builder.buildQuery()
+		if err != nil {
+			t.Fatalf("buildQuery failed: %v", err)
+		}
+		if tt.filter != query.SearchQuery {
+			t.Errorf("Expected filter to match builder output '%s', got '%s'", query.SearchQuery, tt.filter)
+		}
+	}
+
+	if got := CategoryFilter(); got != "" {
+		t.Errorf("Expected empty filter for no categories, got '%s'", got)
+	}
+}
+
 func TestQueryBuilder_Author(t *testing.T) {
 	client := NewClient()
 	qb := client.NewQuery().Author("Einstein")
@@ -105,6 +166,31 @@ func TestQueryBuilder_Abstract(t *testing.T) {
 	}
 }
 
+func TestQueryBuilder_All(t *testing.T) {
+	client := NewClient()
+	qb := client.NewQuery().All("electron")
+
+	query, err := qb.buildQuery()
+	if err != nil {
+		t.Fatalf("buildQuery failed: %v", err)
+	}
+
+	if query.SearchQuery != "all:electron" {
+		t.Errorf("Expected search query 'all:electron', got '%s'", query.SearchQuery)
+	}
+
+	qb = client.NewQuery().All("electron").All("proton")
+	query, err = qb.buildQuery()
+	if err != nil {
+		t.Fatalf("buildQuery failed: %v", err)
+	}
+
+	expected := "(all:electron OR all:proton)"
+	if query.SearchQuery != expected {
+		t.Errorf("Expected search query '%s', got '%s'", expected, query.SearchQuery)
+	}
+}
+
 func TestQueryBuilder_DateRange(t *testing.T) {
 	client := NewClient()
 	from := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
@@ -148,6 +234,37 @@ func TestQueryBuilder_SortBy(t *testing.T) {
 	}
 }
 
+func TestQueryBuilder_SortByFieldAndOrder(t *testing.T) {
+	client := NewClient()
+
+	// Setting only the field keeps the default descending order
+	query, err := client.NewQuery().SearchQuery("test").SortByField(SortByLastUpdatedDate).buildQuery()
+	if err != nil {
+		t.Fatalf("buildQuery failed: %v", err)
+	}
+	if query.SortBy != string(SortByLastUpdatedDate) || query.SortOrder != string(SortOrderDescending) {
+		t.Errorf("Expected lastUpdatedDate/descending, got %s/%s", query.SortBy, query.SortOrder)
+	}
+
+	// Setting only the order keeps the default relevance criterion
+	query, err = client.NewQuery().SearchQuery("test").Ascending().buildQuery()
+	if err != nil {
+		t.Fatalf("buildQuery failed: %v", err)
+	}
+	if query.SortBy != string(SortByRelevance) || query.SortOrder != string(SortOrderAscending) {
+		t.Errorf("Expected relevance/ascending, got %s/%s", query.SortBy, query.SortOrder)
+	}
+
+	// Order and field can be set in either sequence
+	query, err = client.NewQuery().SearchQuery("test").Ascending().SortByField(SortBySubmittedDate).Descending().buildQuery()
+	if err != nil {
+		t.Fatalf("buildQuery failed: %v", err)
+	}
+	if query.SortBy != string(SortBySubmittedDate) || query.SortOrder != string(SortOrderDescending) {
+		t.Errorf("Expected submittedDate/descending, got %s/%s", query.SortBy, query.SortOrder)
+	}
+}
+
 func TestQueryBuilder_MaxResults(t *testing.T) {
 	client := NewClient()
 	qb := client.NewQuery().
@@ -232,12 +349,304 @@ func TestQueryBuilder_ComplexQueryWithOperators(t *testing.T) {
 		t.Fatalf("buildQuery failed: %v", err)
 	}
 
-	expected := "(quantum AND computing OR machine learning)"
+	expected := "((quantum AND computing) OR (machine learning))"
+	if query.SearchQuery != expected {
+		t.Errorf("Expected search query '%s', got '%s'", expected, query.SearchQuery)
+	}
+}
+
+func TestSearchExpression(t *testing.T) {
+	tests := []struct {
+		terms    []string
+		expected string
+	}{
+		{nil, ""},
+		{[]string{"quantum"}, "quantum"},
+		{[]string{"quantum", "computing"}, "quantum computing"},
+		{[]string{"a", "OR", "b", "AND", "c"}, "a OR (b AND c)"},
+		{[]string{"a", "AND", "b", "OR", "c", "ANDNOT", "d"}, "(a AND b) OR (c ANDNOT d)"},
+		{[]string{"a", "AND", "b", "ANDNOT", "c"}, "a AND b ANDNOT c"},
+		{[]string{"a", "OR", "b", "OR", "c"}, "a OR b OR c"},
+		{[]string{"AND", "a", "OR", "OR", "b", "AND"}, "a OR b"},
+	}
+
+	for _, tt := range tests {
+		if got := searchExpression(tt.terms); got != tt.expected {
+			t.Errorf("searchExpression(%q): expected %q, got %q", tt.terms, tt.expected, got)
+		}
+	}
+}
+
+func TestQueryBuilder_Merge(t *testing.T) {
+	client := NewClient()
+	categories := client.NewQuery().Categories(CategoryCSAI, CategoryCSLG).MaxResults(50)
+	authors := client.NewQuery().Author("Hinton").SortBy(SortBySubmittedDate, SortOrderAscending)
+
+	query, err := categories.Merge(authors).buildQuery()
+	if err != nil {
+		t.Fatalf("buildQuery failed: %v", err)
+	}
+
+	expected := "(cat:cs.AI OR cat:cs.LG) AND au:Hinton"
+	if query.SearchQuery != expected {
+		t.Errorf("Expected search query '%s', got '%s'", expected, query.SearchQuery)
+	}
+
+	// Defaults in the merged builder don't override explicit settings
+	if query.MaxResults != 50 {
+		t.Errorf("Expected MaxResults 50 to survive the merge, got %d", query.MaxResults)
+	}
+	if query.SortBy != string(SortBySubmittedDate) || query.SortOrder != string(SortOrderAscending) {
+		t.Errorf("Expected merged sort submittedDate/ascending, got %s/%s", query.SortBy, query.SortOrder)
+	}
+
+	// Search terms are joined with AND and errors are surfaced
+	merged := client.NewQuery().SearchQuery("quantum").Merge(client.NewQuery().SearchQuery("computing"))
+	query, err = merged.buildQuery()
+	if err != nil {
+		t.Fatalf("buildQuery failed: %v", err)
+	}
+	if query.SearchQuery != "(quantum AND computing)" {
+		t.Errorf("Expected '(quantum AND computing)', got '%s'", query.SearchQuery)
+	}
+
+	// Operators on either side stay grouped within that side
+	merged = client.NewQuery().SearchQuery("quantum").OR().SearchQuery("qubit").
+		Merge(client.NewQuery().SearchQuery("error").AND().SearchQuery("correction"))
+	query, err = merged.buildQuery()
+	if err != nil {
+		t.Fatalf("buildQuery failed: %v", err)
+	}
+	if query.SearchQuery != "((quantum OR qubit) AND (error AND correction))" {
+		t.Errorf("Expected grouped merge, got '%s'", query.SearchQuery)
+	}
+
+	// A client's DefaultMaxResults is a default too, while explicit settings win even
+	// when they equal the package defaults
+	custom := NewClientWithOptions(ClientOptions{DefaultMaxResults: 100})
+	merged = client.NewQuery().SearchQuery("test").MaxResults(500).SortByField(SortBySubmittedDate).
+		Merge(custom.NewQuery().Author("Hinton"))
+	query, err = merged.buildQuery()
+	if err != nil {
+		t.Fatalf("buildQuery failed: %v", err)
+	}
+	if query.MaxResults != 500 || query.SortBy != string(SortBySubmittedDate) {
+		t.Errorf("Expected MaxResults 500 sorted by submittedDate, got %d sorted by %s", query.MaxResults, query.SortBy)
+	}
+	merged = custom.NewQuery().SearchQuery("test").MaxResults(500).Limit(10).
+		Merge(client.NewQuery().MaxResults(defaultMaxResults).Limit(defaultLimit).SortByField(SortByRelevance))
+	query, err = merged.buildQuery()
+	if err != nil {
+		t.Fatalf("buildQuery failed: %v", err)
+	}
+	if query.MaxResults != defaultMaxResults || query.Limit != defaultLimit || query.SortBy != string(SortByRelevance) {
+		t.Errorf("Expected the explicit settings to win, got MaxResults %d, Limit %d, SortBy %s",
+			query.MaxResults, query.Limit, query.SortBy)
+	}
+
+	// other's primary category replaces qb's rather than ANDing both
+	merged = client.NewQuery().PrimaryCategory(CategoryCSAI).Category(CategoryCSCL).
+		Merge(client.NewQuery().PrimaryCategory(CategoryCSLG))
+	query, err = merged.buildQuery()
+	if err != nil {
+		t.Fatalf("buildQuery failed: %v", err)
+	}
+	if query.SearchQuery != "cat:cs.CL AND cat:cs.LG" || query.PrimaryCategory != string(CategoryCSLG) {
+		t.Errorf("Expected primary category cs.LG alone, got '%s' with primary %s", query.SearchQuery, query.PrimaryCategory)
+	}
+
+	merged = client.NewQuery().SearchQuery("test").Merge(client.NewQuery().Start(-1))
+	if _, err := merged.buildQuery(); err == nil {
+		t.Error("Expected merged builder to surface the other builder's error")
+	}
+}
+
+func TestQueryBuilder_AnyField(t *testing.T) {
+	tests := []struct {
+		name     string
+		build    func(*QueryBuilder) *QueryBuilder
+		expected string
+	}{
+		{
+			"single field",
+			func(qb *QueryBuilder) *QueryBuilder { return qb.AnyField("transformer", FieldTitle) },
+			"ti:transformer",
+		},
+		{
+			"title or abstract",
+			func(qb *QueryBuilder) *QueryBuilder {
+				return qb.AnyField("transformer", FieldTitle, FieldAbstract).Category(CategoryCSCL)
+			},
+			"cat:cs.CL AND (ti:transformer OR abs:transformer)",
+		},
+		{
+			"every field kind",
+			func(qb *QueryBuilder) *QueryBuilder {
+				return qb.AnyField("Hinton", FieldAuthor, FieldComment, FieldAll)
+			},
+			"(au:Hinton OR co:Hinton OR all:Hinton)",
+		},
+		{
+			"duplicate fields",
+			func(qb *QueryBuilder) *QueryBuilder { return qb.AnyField("qubit", FieldAbstract, FieldAbstract) },
+			"abs:qubit",
+		},
+		{
+			"no fields",
+			func(qb *QueryBuilder) *QueryBuilder { return qb.AnyField("qubit") },
+			"all:qubit",
+		},
+		{
+			"several groups",
+			func(qb *QueryBuilder) *QueryBuilder {
+				return qb.AnyField("a", FieldTitle, FieldAbstract).AnyField("b", FieldTitle, FieldAbstract)
+			},
+			"(ti:a OR abs:a) AND (ti:b OR abs:b)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, err := tt.build(NewClient().NewQuery()).buildQuery()
+			if err != nil {
+				t.Fatalf("buildQuery failed: %v", err)
+			}
+			if query.SearchQuery != tt.expected {
+				t.Errorf("Expected search query '%s', got '%s'", tt.expected, query.SearchQuery)
+			}
+		})
+	}
+
+	_, err := NewClient().NewQuery().AnyField("x", FieldTitle, QueryField("bogus")).buildQuery()
+	if !IsInvalidQuery(err) {
+		t.Errorf("Expected an invalid query error for an undefined field, got %v", err)
+	}
+
+	// An empty text adds nothing
+	if err := NewClient().NewQuery().AnyField("", FieldTitle).Validate(); err == nil {
+		t.Error("Expected an empty AnyField to leave the query empty")
+	}
+}
+
+func TestQueryBuilder_ExcludeAuthor(t *testing.T) {
+	tests := []struct {
+		name     string
+		build    func(*QueryBuilder) *QueryBuilder
+		expected string
+	}{
+		{
+			"single word",
+			func(qb *QueryBuilder) *QueryBuilder { return qb.Category(CategoryCSLG).ExcludeAuthor("Hinton") },
+			"cat:cs.LG ANDNOT au:Hinton",
+		},
+		{
+			"quoted full name",
+			func(qb *QueryBuilder) *QueryBuilder {
+				return qb.Category(CategoryCSLG).ExcludeAuthor("  Geoffrey \"E.\"  Hinton ")
+			},
+			`cat:cs.LG ANDNOT au:"Geoffrey E. Hinton"`,
+		},
+		{
+			"several filters and authors",
+			func(qb *QueryBuilder) *QueryBuilder {
+				return qb.SearchQuery("attention").Category(CategoryCSLG).
+					ExcludeAuthor("Hinton").ExcludeAuthor("Yann LeCun").ExcludeAuthor("Hinton")
+			},
+			`((attention) AND cat:cs.LG) ANDNOT (au:Hinton OR au:"Yann LeCun")`,
+		},
+		{
+			"empty name",
+			func(qb *QueryBuilder) *QueryBuilder { return qb.Author("Bengio").ExcludeAuthor(` "" `) },
+			"au:Bengio",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, err := tt.build(NewClient().NewQuery()).buildQuery()
+			if err != nil {
+				t.Fatalf("buildQuery failed: %v", err)
+			}
+			if query.SearchQuery != tt.expected {
+				t.Errorf("Expected search query '%s', got '%s'", tt.expected, query.SearchQuery)
+			}
+		})
+	}
+
+	// Exclusions alone would send a dangling ANDNOT
+	_, err := NewClient().NewQuery().ExcludeAuthor("Hinton").buildQuery()
+	if !IsInvalidQuery(err) {
+		t.Errorf("Expected an invalid query error for exclusions only, got %v", err)
+	}
+}
+
+func TestQueryBuilder_CrossListedIn(t *testing.T) {
+	query, err := NewClient().NewQuery().Category(CategoryCSLG).CrossListedIn(CategoryStatML).buildQuery()
+	if err != nil {
+		t.Fatalf("buildQuery failed: %v", err)
+	}
+
+	expected := "cat:cs.LG AND cat:stat.ML"
 	if query.SearchQuery != expected {
 		t.Errorf("Expected search query '%s', got '%s'", expected, query.SearchQuery)
 	}
 }
 
+func TestQueryBuilder_PrimaryCategory(t *testing.T) {
+	query, err := NewClient().NewQuery().PrimaryCategory(CategoryStatML).PrimaryCategory(CategoryCSLG).buildQuery()
+	if err != nil {
+		t.Fatalf("buildQuery failed: %v", err)
+	}
+	if query.SearchQuery != "cat:cs.LG" {
+		t.Errorf("Expected search query 'cat:cs.LG', got '%s'", query.SearchQuery)
+	}
+	if query.PrimaryCategory != "cs.LG" {
+		t.Errorf("Expected PrimaryCategory 'cs.LG', got '%s'", query.PrimaryCategory)
+	}
+
+	// Odd entries are stat.ML papers cross-listed in cs.LG. The category order is the
+	// reverse of the primary category's, so only the primary_category element tells them apart.
+	const total = 8
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start, _ := strconv.Atoi(r.URL.Query().Get("start"))
+		maxResults, _ := strconv.Atoi(r.URL.Query().Get("max_results"))
+
+		var b strings.Builder
+		fmt.Fprintf(&b, `<?xml version="1.0" encoding="UTF-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom" xmlns:opensearch="http://a9.com/-/spec/opensearch/1.1/" xmlns:arxiv="http://arxiv.org/schemas/atom">
+  <opensearch:totalResults>%d</opensearch:totalResults>
+  <opensearch:startIndex>%d</opensearch:startIndex>
+  <opensearch:itemsPerPage>%d</opensearch:itemsPerPage>`, total, start, maxResults)
+		for i := start; i < min(start+maxResults, total); i++ {
+			categories := `<arxiv:primary_category term="cs.LG"/><category term="stat.ML"/><category term="cs.LG"/>`
+			if i%2 == 1 {
+				categories = `<arxiv:primary_category term="stat.ML"/><category term="cs.LG"/><category term="stat.ML"/>`
+			}
+			fmt.Fprintf(&b, `
+  <entry><id>http://arxiv.org/abs/2301.%05dv1</id><title>Paper %d</title>
+    <published>2023-01-01T00:00:00Z</published><updated>2023-01-01T00:00:00Z</updated>%s</entry>`, i, i, categories)
+		}
+		b.WriteString("\n</feed>")
+		w.Write([]byte(b.String()))
+	}))
+	defer server.Close()
+
+	client := newFastClient(server.URL)
+	papers, err := client.NewQuery().PrimaryCategory(CategoryCSLG).MaxResults(2).Limit(3).Iterator(context.Background()).Collect()
+	if err != nil {
+		t.Fatalf("Collect failed: %v", err)
+	}
+
+	var ids []string
+	for _, paper := range papers {
+		ids = append(ids, paper.ID)
+	}
+	expected := []string{"2301.00000v1", "2301.00002v1", "2301.00004v1"}
+	if !slices.Equal(ids, expected) {
+		t.Errorf("Expected only primary cs.LG papers %v, got %v", expected, ids)
+	}
+}
+
 func TestQueryBuilder_Validation(t *testing.T) {
 	client := NewClient()
 
@@ -262,6 +671,13 @@ func TestQueryBuilder_Validation(t *testing.T) {
 		t.Error("Expected validation error for negative start")
 	}
 
+	// Test unknown sort criterion
+	qb = client.NewQuery().SearchQuery("test").SortByField("submitedDate")
+	err = qb.Validate()
+	if err == nil {
+		t.Error("Expected validation error for unknown sort criterion")
+	}
+
 	// Test valid query
 	qb = client.NewQuery().SearchQuery("test")
 	err = qb.Validate()
@@ -270,6 +686,30 @@ func TestQueryBuilder_Validation(t *testing.T) {
 	}
 }
 
+func TestQueryBuilder_DanglingOperators(t *testing.T) {
+	client := NewClient()
+	tests := map[string]*QueryBuilder{
+		"leading":     client.NewQuery().AND().SearchQuery("computing"),
+		"trailing":    client.NewQuery().SearchQuery("quantum").OR(),
+		"consecutive": client.NewQuery().SearchQuery("quantum").AND().OR().SearchQuery("computing"),
+		"alone":       client.NewQuery().ANDNOT().Category(CategoryCSAI),
+	}
+
+	for name, qb := range tests {
+		if _, err := qb.buildQuery(); !IsInvalidQuery(err) {
+			t.Errorf("%s: expected invalid query error from buildQuery, got %v", name, err)
+		}
+		if err := qb.Validate(); !IsInvalidQuery(err) {
+			t.Errorf("%s: expected invalid query error from Validate, got %v", name, err)
+		}
+	}
+
+	// An ID list query ignores the search terms
+	if _, err := client.NewQuery().AND().IDList("1234.5678").buildQuery(); err != nil {
+		t.Errorf("Expected ID list query to ignore search terms, got %v", err)
+	}
+}
+
 func TestQueryBuilder_ErrorAccumulation(t *testing.T) {
 	client := NewClient()
 	qb := client.NewQuery().
@@ -342,3 +782,49 @@ func TestQueryBuilder_ChainedCalls(t *testing.T) {
 		t.Errorf("Expected SortOrder to be '%s', got '%s'", SortOrderDescending, query.SortOrder)
 	}
 }
+
+func TestQueryBuilder_Stream(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("start") != "" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/xml")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(generateFeed(5, 0, 2)))
+	}))
+	defer server.Close()
+
+	client := NewClientWithOptions(ClientOptions{RateLimit: time.Nanosecond, RetryDelay: time.Millisecond})
+	client.baseURL = server.URL
+
+	// The second page fails after the first page's papers were yielded
+	var ids []string
+	var streamErr error
+	for paper, err := range client.NewQuery().SearchQuery("test").MaxResults(2).Stream(context.Background()) {
+		if err != nil {
+			streamErr = err
+			break
+		}
+		ids = append(ids, paper.ID)
+	}
+
+	if len(ids) != 2 || ids[0] != "2301.00000v1" || ids[1] != "2301.00001v1" {
+		t.Errorf("Expected the first page before the error, got %v", ids)
+	}
+	if streamErr == nil {
+		t.Fatal("Expected the failed second page to be yielded as an error")
+	}
+
+	// An invalid query is yielded as the only element
+	count := 0
+	for paper, err := range client.NewQuery().Stream(context.Background()) {
+		count++
+		if paper != nil || !IsInvalidQuery(err) {
+			t.Errorf("Expected a single invalid query error, got %v, %v", paper, err)
+		}
+	}
+	if count != 1 {
+		t.Errorf("Expected 1 element for an invalid query, got %d", count)
+	}
+}
@@ -0,0 +1,63 @@
+package arxiv
+
+import (
+	"context"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// searchFlights shares one request among concurrent identical searches, keyed by
+// Query.CacheKey
+type searchFlights struct {
+	group singleflight.Group
+}
+
+// newSearchFlights creates a flight group, or returns nil if enabled is false
+func newSearchFlights(enabled bool) *searchFlights {
+	if !enabled {
+		return nil
+	}
+	return &searchFlights{}
+}
+
+// do runs search once for all concurrent callers with the same key and gives each caller
+// its own copy of the results or error. The search keeps the first caller's context values
+// but not its cancellation, so a caller that stops waiting doesn't fail the others; it runs
+// until it completes, bounded by the query's Timeout and the client's retry settings.
+func (g *searchFlights) do(ctx context.Context, key string, search func(context.Context) (*SearchResults, error)) (*SearchResults, error) {
+	callCtx := context.WithoutCancel(ctx)
+	ch := g.group.DoChan(key, func() (any, error) {
+		return search(callCtx)
+	})
+
+	select {
+	case res := <-ch:
+		if res.Err != nil {
+			return nil, copyError(res.Err)
+		}
+		return copySearchResults(res.Val.(*SearchResults)), nil
+	case <-ctx.Done():
+		return nil, newContextError(ctx)
+	}
+}
+
+// copySearchResults returns a deep copy of results, cloning every paper
+func copySearchResults(results *SearchResults) *SearchResults {
+	copied := *results
+	copied.Papers = make([]Paper, len(results.Papers))
+	for i := range results.Papers {
+		copied.Papers[i] = *results.Papers[i].Clone()
+	}
+	return &copied
+}
+
+// copyError returns a copy of err if it is an *APIError, so that callers sharing a search
+// can't see each other's changes to it, and err itself otherwise
+func copyError(err error) error {
+	apiErr, ok := err.(*APIError)
+	if !ok {
+		return err
+	}
+	copied := *apiErr
+	return &copied
+}
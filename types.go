@@ -1,7 +1,15 @@
 package arxiv
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -17,7 +25,12 @@ type Paper struct {
 	DOI         string    `json:"doi,omitempty"`
 	JournalRef  string    `json:"journal_ref,omitempty"`
 	Comment     string    `json:"comment,omitempty"`
+	ACMClass    string    `json:"acm_class,omitempty"` // ACM Computing Classification, e.g. "I.2.6; H.3.3"
+	MSCClass    string    `json:"msc_class,omitempty"` // Mathematics Subject Classification, e.g. "14J60 (Primary)"
 	Links       []Link    `json:"links"`
+
+	relevanceRank   int    // 1-based position in relevance-sorted results, 0 if unknown
+	primaryCategory string // Primary category given by the feed, "" if unknown
 }
 
 // Author represents a paper author
@@ -26,6 +39,23 @@ type Author struct {
 	Affiliation string `json:"affiliation,omitempty"`
 }
 
+// String returns a one-line summary of the paper for logging and debugging
+func (p *Paper) String() string {
+	names := make([]string, len(p.Authors))
+	for i, author := range p.Authors {
+		names[i] = author.Name
+	}
+	return fmt.Sprintf("[%s] %s — %s (%s)", p.ID, p.Title, strings.Join(names, ", "), p.PublishedAt.Format("2006-01-02"))
+}
+
+// String returns the author's name, followed by the affiliation in parentheses if known
+func (a Author) String() string {
+	if a.Affiliation != "" {
+		return fmt.Sprintf("%s (%s)", a.Name, a.Affiliation)
+	}
+	return a.Name
+}
+
 // Link represents a link associated with a paper
 type Link struct {
 	Href  string `json:"href"`
@@ -60,14 +90,233 @@ type Query struct {
 	// Date range filtering
 	SubmittedDateFrom *time.Time
 	SubmittedDateTo   *time.Time
+
+	// ErrorOnEmpty makes Search fail with an ErrorTypeNotFound error when nothing matches the
+	// query, as GetByID does for an unknown ID, instead of returning empty results
+	ErrorOnEmpty bool
+
+	// SkipAbstract leaves Paper.Abstract empty, skipping the decoding of every summary.
+	// It saves allocations when harvesting IDs and titles in bulk.
+	SkipAbstract bool
+
+	// StrictDateFilter drops papers whose PublishedAt falls outside SubmittedDateFrom and
+	// SubmittedDateTo after fetching. arXiv matches the date range at day granularity and
+	// may return papers just outside it; full timestamps are compared here, so pass the end
+	// of the day as SubmittedDateTo to include that day.
+	StrictDateFilter bool
+
+	// PrimaryCategory drops papers whose primary category, as reported by
+	// Paper.PrimaryCategory, is not this one, e.g. "cs.LG". arXiv's cat: search also matches
+	// cross-lists, so pair it with a cat: clause for the same category;
+	// QueryBuilder.PrimaryCategory sets both.
+	PrimaryCategory string
+
+	// CopyPapers makes iterators yield a Clone of each paper instead of a pointer into the
+	// fetched page, so callers can modify the papers they receive. It only affects
+	// iterators and costs an allocation per paper.
+	CopyPapers bool
+
+	// Timeout bounds each HTTP request made for this query (0 = no per-query timeout).
+	// The client Timeout, the context deadline and this value all apply; the most restrictive wins.
+	Timeout time.Duration
+}
+
+// Validate checks the query's sort values, which arXiv silently ignores when misspelled.
+// Empty values are valid and fall back to the defaults.
+func (q *Query) Validate() error {
+	if q.SortBy != "" && !SortCriterion(q.SortBy).IsValid() {
+		return NewAPIError(ErrorTypeInvalidQuery, fmt.Sprintf("invalid sortBy %q: must be relevance, lastUpdatedDate or submittedDate", q.SortBy), nil)
+	}
+	if q.SortOrder != "" && !SortOrder(q.SortOrder).IsValid() {
+		return NewAPIError(ErrorTypeInvalidQuery, fmt.Sprintf("invalid sortOrder %q: must be ascending or descending", q.SortOrder), nil)
+	}
+	return nil
+}
+
+const (
+	// arxiv.org pages for humans, as opposed to the export API
+	webAbsURL    = "https://arxiv.org/abs/"
+	webSearchURL = "https://arxiv.org/search/"
+)
+
+// webSearchTypes maps API field prefixes to the searchtype values of the arxiv.org search form
+var webSearchTypes = map[string]string{
+	"all": "all",
+	"ti":  "title",
+	"au":  "author",
+	"abs": "abstract",
+	"co":  "comments",
+	"jr":  "journal_ref",
+	"rn":  "report_num",
+	"id":  "paper_id",
+}
+
+// WebURL returns an arxiv.org link showing the query's results in a browser: the abstract
+// page for a single ID, or the search page for a search on one field such as "au:Hinton"
+// or "quantum computing". It returns "" for queries the search page cannot express,
+// including boolean expressions, categories, date ranges and several IDs; use
+// Client.QueryURL to share those.
+func (q *Query) WebURL() string {
+	if len(q.IDList) > 0 {
+		if len(q.IDList) == 1 && q.SearchQuery == "" {
+			return webAbsURL + q.IDList[0]
+		}
+		return ""
+	}
+	if q.SubmittedDateFrom != nil || q.SubmittedDateTo != nil {
+		return ""
+	}
+
+	search := strings.TrimSpace(q.SearchQuery)
+	if strings.HasPrefix(search, "(") && strings.HasSuffix(search, ")") {
+		search = strings.TrimSpace(search[1 : len(search)-1])
+	}
+	if search == "" || strings.ContainsAny(search, "()") {
+		return ""
+	}
+	for _, word := range strings.Fields(search) {
+		if isSearchOperator(word) {
+			return ""
+		}
+	}
+
+	searchType := "all"
+	if prefix, value, ok := strings.Cut(search, ":"); ok {
+		if searchType, ok = webSearchTypes[prefix]; !ok || strings.Contains(value, ":") {
+			return ""
+		}
+		search = value
+	}
+
+	params := url.Values{}
+	params.Set("query", search)
+	params.Set("searchtype", searchType)
+	// The search page sorts by announcement date rather than submission date
+	if q.SortBy == string(SortBySubmittedDate) {
+		if q.SortOrder == string(SortOrderAscending) {
+			params.Set("order", "announced_date_first")
+		} else {
+			params.Set("order", "-announced_date_first")
+		}
+	}
+	return webSearchURL + "?" + params.Encode()
+}
+
+// filtersResults reports whether Search drops some of the papers arXiv returns for the query
+func (q *Query) filtersResults() bool {
+	return q.StrictDateFilter || q.PrimaryCategory != ""
+}
+
+// CacheKey returns a stable key identifying the results of the query, a hex-encoded
+// SHA-256 hash suitable for caches and for deduplicating in-flight requests. Queries that
+// make the same API request and filter its results the same way get the same key: the
+// IDList is compared as a set, empty sort values count as their defaults and dates are
+// compared by the calendar day sent to arXiv, in each date's own location, plus the exact
+// instant when StrictDateFilter is set. Timeout and CopyPapers don't affect the key, while
+// MaxResults is taken as is since its default depends on the client.
+func (q *Query) CacheKey() string {
+	params := url.Values{}
+	params.Set("search_query", q.SearchQuery)
+	ids := slices.Clone(q.IDList)
+	slices.Sort(ids)
+	params.Set("id_list", strings.Join(ids, ","))
+	params.Set("start", strconv.Itoa(q.Start))
+	params.Set("max_results", strconv.Itoa(q.MaxResults))
+	params.Set("limit", strconv.Itoa(q.Limit))
+	sortBy, sortOrder := q.SortBy, q.SortOrder
+	if sortBy == "" {
+		sortBy = defaultSortBy
+	}
+	if sortOrder == "" {
+		sortOrder = defaultSortOrder
+	}
+	params.Set("sortBy", sortBy)
+	params.Set("sortOrder", sortOrder)
+	for name, date := range map[string]*time.Time{"from": q.SubmittedDateFrom, "to": q.SubmittedDateTo} {
+		if date == nil {
+			continue
+		}
+		// The request sends the date in its own location; the strict filter compares instants
+		params.Set(name, date.Format("20060102"))
+		if q.StrictDateFilter {
+			params.Set(name+"_instant", date.UTC().Format(time.RFC3339Nano))
+		}
+	}
+	params.Set("error_on_empty", strconv.FormatBool(q.ErrorOnEmpty))
+	params.Set("skip_abstract", strconv.FormatBool(q.SkipAbstract))
+	params.Set("strict_date_filter", strconv.FormatBool(q.StrictDateFilter))
+	params.Set("primary_category", q.PrimaryCategory)
+
+	// Encode sorts the parameters by name
+	sum := sha256.Sum256([]byte(params.Encode()))
+	return hex.EncodeToString(sum[:])
 }
 
 // SearchResults represents the response from arXiv API
 type SearchResults struct {
-	Papers       []Paper `json:"papers"`         // List of papers returned by the search
-	TotalCount   int     `json:"total_count"`    // Total number of papers matching the query (not fetched papers)
-	StartIndex   int     `json:"start_index"`    // Start index of the current page (0-based)
-	ItemsPerPage int     `json:"items_per_page"` // Number of papers in the current page
+	Papers       []Paper `json:"papers"`               // List of papers returned by the search
+	TotalCount   int     `json:"total_count"`          // Total number of papers matching the query (not fetched papers)
+	StartIndex   int     `json:"start_index"`          // Start index of the current page (0-based)
+	ItemsPerPage int     `json:"items_per_page"`       // Number of papers in the current page
+	SortBy       string  `json:"sort_by,omitempty"`    // Sort criterion of the query, empty for the API default (relevance)
+	SortOrder    string  `json:"sort_order,omitempty"` // Sort order of the query, empty for the API default
+
+	filtered int // Papers of this page dropped client-side, e.g. by Query.StrictDateFilter or Query.PrimaryCategory
+}
+
+// IsEmpty reports whether the results hold no papers, e.g. because the query matched nothing.
+// Search reports no matches as empty results rather than an error unless Query.ErrorOnEmpty is set.
+func (r *SearchResults) IsEmpty() bool {
+	return len(r.Papers) == 0
+}
+
+// Filter returns the papers satisfying pred, in order, like Iterator.Filter for results
+// already fetched. The pointers refer to r.Papers, so changes to them show in r.
+func (r *SearchResults) Filter(pred func(*Paper) bool) []*Paper {
+	var papers []*Paper
+	for i := range r.Papers {
+		if pred(&r.Papers[i]) {
+			papers = append(papers, &r.Papers[i])
+		}
+	}
+	return papers
+}
+
+// TitlesMatching returns the papers whose title contains substr, ignoring case
+func (r *SearchResults) TitlesMatching(substr string) []*Paper {
+	return r.Filter(TitleContains(substr))
+}
+
+// pageLen returns the number of entries arXiv returned for the page, including
+// papers dropped client-side, so paging continues after all of them
+func (r *SearchResults) pageLen() int {
+	return len(r.Papers) + r.filtered
+}
+
+// searchResultsJSON is the JSON form of SearchResults. Fields are listed explicitly so
+// that fields added to SearchResults stay out of the JSON unless added here too.
+type searchResultsJSON struct {
+	Papers       []Paper `json:"papers"`
+	TotalCount   int     `json:"total_count"`
+	StartIndex   int     `json:"start_index"`
+	ItemsPerPage int     `json:"items_per_page"`
+	FetchedCount int     `json:"fetched_count"`
+	SortBy       string  `json:"sort_by,omitempty"`
+	SortOrder    string  `json:"sort_order,omitempty"`
+}
+
+// MarshalJSON encodes the results for API responses, adding fetched_count, the number
+// of papers in the page, next to total_count. Decoding with encoding/json ignores it.
+func (r SearchResults) MarshalJSON() ([]byte, error) {
+	return json.Marshal(searchResultsJSON{
+		Papers:       r.Papers,
+		TotalCount:   r.TotalCount,
+		StartIndex:   r.StartIndex,
+		ItemsPerPage: r.ItemsPerPage,
+		FetchedCount: len(r.Papers),
+		SortBy:       r.SortBy,
+		SortOrder:    r.SortOrder,
+	})
 }
 
 // ErrorType represents the type of error that occurred
@@ -82,6 +331,9 @@ const (
 	ErrorTypeInvalidQuery
 	ErrorTypeNoEntry // Check https://github.com/lukasschwab/arxiv.py/issues/129
 	ErrorTypeUnknown
+	ErrorTypeServerError // Appended after ErrorTypeUnknown to keep the earlier values stable
+
+	numErrorTypes // Number of error types; new types go before it
 )
 
 // String returns a string representation of the error type
@@ -99,18 +351,54 @@ func (et ErrorType) String() string {
 		return "not_found"
 	case ErrorTypeInvalidQuery:
 		return "invalid_query"
+	case ErrorTypeServerError:
+		return "server_error"
 	default:
 		return "unknown"
 	}
 }
 
+// Sentinel errors for use with errors.Is; an APIError matches the sentinel for its Type
+var (
+	ErrRateLimited  = errors.New("arxiv: rate limit exceeded")
+	ErrTimeout      = errors.New("arxiv: request timed out")
+	ErrParsing      = errors.New("arxiv: failed to parse response")
+	ErrNetwork      = errors.New("arxiv: network error")
+	ErrNotFound     = errors.New("arxiv: paper not found")
+	ErrInvalidQuery = errors.New("arxiv: invalid query")
+	ErrServerError  = errors.New("arxiv: server unavailable")
+
+	// ErrResponseTooLarge is wrapped by the error returned when a response exceeds ClientOptions.MaxResponseBytes
+	ErrResponseTooLarge = errors.New("arxiv: response too large")
+
+	// ErrTruncatedResponse is wrapped by the ErrorTypeParsing error returned when a response
+	// body ends mid-document, as opposed to a complete body that isn't a valid feed
+	ErrTruncatedResponse = errors.New("arxiv: response truncated")
+
+	// ErrUnsupportedCharset is wrapped by the ErrorTypeParsing error returned when a response
+	// declares an XML encoding the client can't decode; retrying won't help
+	ErrUnsupportedCharset = errors.New("arxiv: unsupported charset")
+)
+
+// sentinelErrors maps each error type to its sentinel error
+var sentinelErrors = map[ErrorType]error{
+	ErrorTypeRateLimit:    ErrRateLimited,
+	ErrorTypeTimeout:      ErrTimeout,
+	ErrorTypeParsing:      ErrParsing,
+	ErrorTypeNetwork:      ErrNetwork,
+	ErrorTypeNotFound:     ErrNotFound,
+	ErrorTypeInvalidQuery: ErrInvalidQuery,
+	ErrorTypeServerError:  ErrServerError,
+}
+
 // APIError represents a detailed arXiv API error
 type APIError struct {
-	Type    ErrorType `json:"type"`
-	Message string    `json:"message"`
-	Code    int       `json:"code,omitempty"`
-	Retry   bool      `json:"retry"`
-	Err     error     `json:"-"`
+	Type     ErrorType `json:"type"`
+	Message  string    `json:"message"`
+	Code     int       `json:"code,omitempty"`
+	Retry    bool      `json:"retry"`
+	Attempts int       `json:"attempts,omitempty"` // Attempts made before giving up; 0 if the request failed on its only attempt
+	Err      error     `json:"-"`
 }
 
 func (e *APIError) Error() string {
@@ -124,6 +412,12 @@ func (e *APIError) Unwrap() error {
 	return e.Err
 }
 
+// Is reports whether target is the sentinel error for this error's type
+func (e *APIError) Is(target error) bool {
+	sentinel, ok := sentinelErrors[e.Type]
+	return ok && target == sentinel
+}
+
 // NewAPIError creates a new APIError
 func NewAPIError(errorType ErrorType, message string, err error) *APIError {
 	retry := errorType == ErrorTypeRateLimit || errorType == ErrorTypeTimeout || errorType == ErrorTypeNetwork || errorType == ErrorTypeNoEntry
@@ -135,6 +429,46 @@ func NewAPIError(errorType ErrorType, message string, err error) *APIError {
 	}
 }
 
+// IsNotFound reports whether err is an APIError of type ErrorTypeNotFound
+func IsNotFound(err error) bool {
+	return hasErrorType(err, ErrorTypeNotFound)
+}
+
+// IsRateLimited reports whether err is an APIError of type ErrorTypeRateLimit
+func IsRateLimited(err error) bool {
+	return hasErrorType(err, ErrorTypeRateLimit)
+}
+
+// IsInvalidQuery reports whether err is an APIError of type ErrorTypeInvalidQuery
+func IsInvalidQuery(err error) bool {
+	return hasErrorType(err, ErrorTypeInvalidQuery)
+}
+
+// IsRetryable reports whether err is an APIError that is worth retrying
+func IsRetryable(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.Retry
+}
+
+// IsTransient reports whether err is caused by a condition that is likely to pass, so
+// the same request may succeed later: rate limiting, timeouts, network failures, truncated
+// responses and an open circuit breaker. Invalid queries, missing papers, malformed
+// responses and ended contexts are not transient. Unlike IsRetryable, it also covers
+// errors the client doesn't retry by itself, such as an open circuit breaker.
+func IsTransient(err error) bool {
+	if errors.Is(err, ErrTruncatedResponse) {
+		return true
+	}
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && (apiErr.Retry || apiErr.Type == ErrorTypeServerError)
+}
+
+// hasErrorType reports whether err wraps an APIError of the given type
+func hasErrorType(err error, errorType ErrorType) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.Type == errorType
+}
+
 // Legacy Error type for backward compatibility
 type Error struct {
 	Code    int    `json:"code"`
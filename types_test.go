@@ -0,0 +1,325 @@
+package arxiv
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestErrorHelpers(t *testing.T) {
+	tests := []struct {
+		name         string
+		err          error
+		notFound     bool
+		rateLimited  bool
+		invalidQuery bool
+		retryable    bool
+	}{
+		{
+			name:     "not found",
+			err:      NewAPIError(ErrorTypeNotFound, "missing", nil),
+			notFound: true,
+		},
+		{
+			name:        "rate limit",
+			err:         NewAPIError(ErrorTypeRateLimit, "slow down", nil),
+			rateLimited: true,
+			retryable:   true,
+		},
+		{
+			name:         "invalid query",
+			err:          NewAPIError(ErrorTypeInvalidQuery, "bad query", nil),
+			invalidQuery: true,
+		},
+		{
+			name:      "network",
+			err:       NewAPIError(ErrorTypeNetwork, "connection reset", nil),
+			retryable: true,
+		},
+		{
+			name:     "wrapped not found",
+			err:      fmt.Errorf("lookup failed: %w", NewAPIError(ErrorTypeNotFound, "missing", nil)),
+			notFound: true,
+		},
+		{
+			name:        "wrapped rate limit",
+			err:         fmt.Errorf("outer: %w", fmt.Errorf("inner: %w", NewAPIError(ErrorTypeRateLimit, "slow down", nil))),
+			rateLimited: true,
+			retryable:   true,
+		},
+		{
+			name: "plain error",
+			err:  errors.New("boom"),
+		},
+		{
+			name: "nil error",
+			err:  nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsNotFound(tt.err); got != tt.notFound {
+				t.Errorf("IsNotFound: expected %v, got %v", tt.notFound, got)
+			}
+			if got := IsRateLimited(tt.err); got != tt.rateLimited {
+				t.Errorf("IsRateLimited: expected %v, got %v", tt.rateLimited, got)
+			}
+			if got := IsInvalidQuery(tt.err); got != tt.invalidQuery {
+				t.Errorf("IsInvalidQuery: expected %v, got %v", tt.invalidQuery, got)
+			}
+			if got := IsRetryable(tt.err); got != tt.retryable {
+				t.Errorf("IsRetryable: expected %v, got %v", tt.retryable, got)
+			}
+		})
+	}
+}
+
+func TestAPIErrorIs(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		target   error
+		expected bool
+	}{
+		{"not found", NewAPIError(ErrorTypeNotFound, "missing", nil), ErrNotFound, true},
+		{"rate limit", NewAPIError(ErrorTypeRateLimit, "slow down", nil), ErrRateLimited, true},
+		{"timeout", NewAPIError(ErrorTypeTimeout, "slow", nil), ErrTimeout, true},
+		{"parsing", NewAPIError(ErrorTypeParsing, "bad xml", nil), ErrParsing, true},
+		{"network", NewAPIError(ErrorTypeNetwork, "reset", nil), ErrNetwork, true},
+		{"invalid query", NewAPIError(ErrorTypeInvalidQuery, "bad", nil), ErrInvalidQuery, true},
+		{"mismatched type", NewAPIError(ErrorTypeNetwork, "reset", nil), ErrNotFound, false},
+		{"wrapped", fmt.Errorf("get: %w", NewAPIError(ErrorTypeNotFound, "missing", nil)), ErrNotFound, true},
+		{"cause", NewAPIError(ErrorTypeNetwork, "reset", errCause), errCause, true},
+		{"unknown type", NewAPIError(ErrorTypeUnknown, "?", nil), ErrNetwork, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := errors.Is(tt.err, tt.target); got != tt.expected {
+				t.Errorf("errors.Is(%v, %v): expected %v, got %v", tt.err, tt.target, tt.expected, got)
+			}
+		})
+	}
+}
+
+// Error types are serialized as ints, so existing values must not shift
+func TestErrorTypeValues(t *testing.T) {
+	if ErrorTypeNoEntry != 6 || ErrorTypeUnknown != 7 || ErrorTypeServerError != 8 {
+		t.Errorf("Expected NoEntry=6, Unknown=7, ServerError=8, got %d, %d, %d",
+			ErrorTypeNoEntry, ErrorTypeUnknown, ErrorTypeServerError)
+	}
+}
+
+var errCause = errors.New("underlying cause")
+
+func TestQueryWebURL(t *testing.T) {
+	date := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		query    *Query
+		expected string
+	}{
+		{"single ID", &Query{IDList: []string{"2301.00001v2"}}, "https://arxiv.org/abs/2301.00001v2"},
+		{"several IDs", &Query{IDList: []string{"2301.00001", "2301.00002"}}, ""},
+		{"plain text", &Query{SearchQuery: "quantum computing"}, "https://arxiv.org/search/?query=quantum+computing&searchtype=all"},
+		{"author", &Query{SearchQuery: "au:Hinton"}, "https://arxiv.org/search/?query=Hinton&searchtype=author"},
+		{"parenthesized title", &Query{SearchQuery: `(ti:"neural networks")`}, "https://arxiv.org/search/?query=%22neural+networks%22&searchtype=title"},
+		{"newest first", &Query{SearchQuery: "abs:qubit", SortBy: "submittedDate"}, "https://arxiv.org/search/?order=-announced_date_first&query=qubit&searchtype=abstract"},
+		{"oldest first", &Query{SearchQuery: "qubit", SortBy: "submittedDate", SortOrder: "ascending"}, "https://arxiv.org/search/?order=announced_date_first&query=qubit&searchtype=all"},
+		{"boolean expression", &Query{SearchQuery: "au:Hinton AND ti:learning"}, ""},
+		{"category", &Query{SearchQuery: "cat:cs.AI"}, ""},
+		{"two fields", &Query{SearchQuery: "au:Hinton cat:cs.AI"}, ""},
+		{"date range", &Query{SearchQuery: "qubit", SubmittedDateFrom: &date}, ""},
+		{"empty", &Query{}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.query.WebURL(); got != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestQueryCacheKey(t *testing.T) {
+	from := time.Date(2023, 1, 1, 9, 0, 0, 0, time.UTC)
+	fromTokyo := from.In(time.FixedZone("JST", 9*60*60))
+	base := Query{
+		SearchQuery:       "cat:cs.LG",
+		IDList:            []string{"2301.00002", "2301.00001"},
+		MaxResults:        10,
+		SubmittedDateFrom: &from,
+	}
+	key := base.CacheKey()
+	if len(key) != 64 {
+		t.Errorf("Expected a 64-character hex key, got %q", key)
+	}
+
+	same := base
+	same.IDList = []string{"2301.00001", "2301.00002"}
+	same.SubmittedDateFrom = &fromTokyo
+	same.SortBy = string(SortByRelevance)
+	same.SortOrder = string(SortOrderDescending)
+	same.Timeout = time.Second
+	if got := same.CacheKey(); got != key {
+		t.Errorf("Expected equivalent queries to share a key, got %s and %s", key, got)
+	}
+	if got := base.CacheKey(); got != key {
+		t.Errorf("Expected a stable key, got %s and %s", key, got)
+	}
+
+	to := from
+	variants := map[string]func(q *Query){
+		"start":       func(q *Query) { q.Start = 10 },
+		"max results": func(q *Query) { q.MaxResults = 20 },
+		"limit":       func(q *Query) { q.Limit = 5 },
+		"sort":        func(q *Query) { q.SortBy = string(SortBySubmittedDate) },
+		"search":      func(q *Query) { q.SearchQuery = "cat:cs.AI" },
+		"ids":         func(q *Query) { q.IDList = q.IDList[:1] },
+		"date from":   func(q *Query) { q.SubmittedDateFrom = nil },
+		"date to":     func(q *Query) { q.SubmittedDateTo = &to },
+		"primary":     func(q *Query) { q.PrimaryCategory = "cs.LG" },
+		"strict":      func(q *Query) { q.StrictDateFilter = true },
+	}
+	for name, change := range variants {
+		q := base
+		change(&q)
+		if q.CacheKey() == key {
+			t.Errorf("Expected a different key when changing %s", name)
+		}
+	}
+
+	// Same instant, but a different submittedDate day in the request
+	lateUTC := time.Date(2024, 1, 1, 23, 30, 0, 0, time.UTC)
+	earlyParis := lateUTC.In(time.FixedZone("CET", 60*60))
+	utcQuery := Query{SearchQuery: "cat:cs.LG", SubmittedDateFrom: &lateUTC}
+	parisQuery := Query{SearchQuery: "cat:cs.LG", SubmittedDateFrom: &earlyParis}
+	if utcQuery.CacheKey() == parisQuery.CacheKey() {
+		t.Error("Expected queries sending different submittedDate days to have different keys")
+	}
+
+	// Different instants on the same day send the same request
+	morning := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	noon := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	morningQuery := Query{SearchQuery: "cat:cs.LG", SubmittedDateFrom: &morning}
+	noonQuery := Query{SearchQuery: "cat:cs.LG", SubmittedDateFrom: &noon}
+	if morningQuery.CacheKey() != noonQuery.CacheKey() {
+		t.Error("Expected queries sending the same submittedDate day to share a key")
+	}
+	morningQuery.StrictDateFilter = true
+	noonQuery.StrictDateFilter = true
+	if morningQuery.CacheKey() == noonQuery.CacheKey() {
+		t.Error("Expected strict date filtering to key on the exact instant")
+	}
+}
+
+func TestSearchResultsFilter(t *testing.T) {
+	results, err := NewClient().parseSearchResponse([]byte(generateFeed(30, 0, 30)), false)
+	if err != nil {
+		t.Fatalf("parseSearchResponse failed: %v", err)
+	}
+
+	even := results.Filter(func(p *Paper) bool {
+		n, _ := strconv.Atoi(strings.TrimPrefix(p.Title, "Test Paper "))
+		return n%2 == 0
+	})
+	if len(even) != 15 || even[0].Title != "Test Paper 0" || even[14].Title != "Test Paper 28" {
+		t.Errorf("Expected the 15 even papers in order, got %d", len(even))
+	}
+
+	// "Test Paper 2" and "Test Paper 20".."Test Paper 29", matched regardless of case
+	matching := results.TitlesMatching("paper 2")
+	if len(matching) != 11 || matching[0].Title != "Test Paper 2" {
+		t.Errorf("Expected 11 papers matching 'paper 2', got %d", len(matching))
+	}
+
+	// The returned papers are the ones in the results
+	matching[0].Title = "Renamed"
+	if results.Papers[2].Title != "Renamed" {
+		t.Error("Expected Filter to return pointers into the results")
+	}
+
+	if none := results.TitlesMatching("no such title"); len(none) != 0 {
+		t.Errorf("Expected no matches, got %d", len(none))
+	}
+}
+
+func TestSearchResultsMarshalJSON(t *testing.T) {
+	results := &SearchResults{
+		Papers:       []Paper{{ID: "2301.00001v1"}, {ID: "2301.00002v1"}},
+		TotalCount:   10,
+		StartIndex:   4,
+		ItemsPerPage: 2,
+		SortBy:       "submittedDate",
+		filtered:     1,
+	}
+
+	data, err := json.Marshal(results)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	keys := slices.Sorted(maps.Keys(fields))
+	expected := []string{"fetched_count", "items_per_page", "papers", "sort_by", "start_index", "total_count"}
+	if !slices.Equal(keys, expected) {
+		t.Errorf("Expected keys %v, got %v", expected, keys)
+	}
+	if string(fields["fetched_count"]) != "2" {
+		t.Errorf("Expected fetched_count 2, got %s", fields["fetched_count"])
+	}
+
+	// Values marshal the same way and the output decodes back into SearchResults
+	valueData, err := json.Marshal(*results)
+	if err != nil || string(valueData) != string(data) {
+		t.Errorf("Expected value and pointer to marshal alike, got %s (%v)", valueData, err)
+	}
+	var decoded SearchResults
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal into SearchResults failed: %v", err)
+	}
+	if decoded.TotalCount != 10 || decoded.StartIndex != 4 || len(decoded.Papers) != 2 || decoded.SortBy != "submittedDate" {
+		t.Errorf("Unexpected round trip: %+v", decoded)
+	}
+}
+
+func TestPaperString(t *testing.T) {
+	paper := &Paper{
+		ID:          "1234.5678v1",
+		Title:       "Test Paper",
+		Authors:     []Author{{Name: "Author One"}, {Name: "Author Two"}},
+		PublishedAt: time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC),
+	}
+
+	expected := "[1234.5678v1] Test Paper — Author One, Author Two (2023-01-01)"
+	if got := paper.String(); got != expected {
+		t.Errorf("Expected '%s', got '%s'", expected, got)
+	}
+
+	if got := fmt.Sprint(paper); got != expected {
+		t.Errorf("Expected fmt to use String, got '%s'", got)
+	}
+}
+
+func TestAuthorString(t *testing.T) {
+	if got := (Author{Name: "Jane Doe"}).String(); got != "Jane Doe" {
+		t.Errorf("Expected 'Jane Doe', got '%s'", got)
+	}
+
+	author := Author{Name: "Jane Doe", Affiliation: "MIT"}
+	if got := author.String(); got != "Jane Doe (MIT)" {
+		t.Errorf("Expected 'Jane Doe (MIT)', got '%s'", got)
+	}
+}